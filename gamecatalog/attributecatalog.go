@@ -0,0 +1,124 @@
+package gamecatalog
+
+import (
+	"fmt"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// Attributes : 角色四项基础属性
+type Attributes struct {
+	Strength  int // 力量
+	Dexterity int // 身法
+	Vitality  int // 外功
+	Energy    int // 内功
+}
+
+// Sum : 四项属性之和
+func (a Attributes) Sum() int {
+	return a.Strength + a.Dexterity + a.Vitality + a.Energy
+}
+
+// AttributesOf : 获取角色当前的四项属性
+func AttributesOf(base *gmstruct.RoleBaseInfo) Attributes {
+	return Attributes{
+		Strength:  int(base.Strength),
+		Dexterity: int(base.Dexterity),
+		Vitality:  int(base.Vitality),
+		Energy:    int(base.Energy),
+	}
+}
+
+// AttributeColumns : 门派初始属性Tab文件的列名
+type AttributeColumns struct {
+	Faction   string // 门派，-1表示未加入门派
+	Strength  string // 力量
+	Dexterity string // 身法
+	Vitality  string // 外功
+	Energy    string // 内功
+}
+
+// DefaultAttributeColumns : 门派初始属性Tab文件的默认列名
+func DefaultAttributeColumns() AttributeColumns {
+	return AttributeColumns{
+		Faction:   "FactionId",
+		Strength:  "Strength",
+		Dexterity: "Dexterity",
+		Vitality:  "Vitality",
+		Energy:    "Energy",
+	}
+}
+
+// DefaultAttributeFile : 门派初始属性Tab文件的默认路径
+const DefaultAttributeFile = "faction_attribute.txt"
+
+// AttributeTable : 门派初始属性表
+type AttributeTable struct {
+	bases map[gmstruct.Faction]Attributes
+}
+
+// NewAttributeTable : 创建一个空的门派初始属性表
+func NewAttributeTable() *AttributeTable {
+	return &AttributeTable{bases: make(map[gmstruct.Faction]Attributes)}
+}
+
+// LoadAttributeTable : 从配置目录加载门派初始属性表
+func LoadAttributeTable(s *Settings, file string, cols AttributeColumns) (*AttributeTable, error) {
+	t, err := s.TabFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	table := NewAttributeTable()
+	if err := table.AddTabFile(t, cols); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// AddTabFile : 将门派初始属性Tab文件中的数据加入属性表
+func (c *AttributeTable) AddTabFile(t *TabFile, cols AttributeColumns) error {
+	if err := t.RequireColumns(cols.Faction, cols.Strength, cols.Dexterity, cols.Vitality, cols.Energy); err != nil {
+		return err
+	}
+
+	for row := 0; row < t.RowCount(); row++ {
+		value := t.String(row, cols.Faction)
+		if value == "" {
+			continue
+		}
+
+		faction, ok := gmstruct.ParseFaction(value)
+		if !ok {
+			return fmt.Errorf("gamecatalog: tab file %s line %d : bad faction %q", t.Name, t.Line(row), value)
+		}
+
+		var a Attributes
+		var err error
+		if a.Strength, err = t.Int(row, cols.Strength); err != nil {
+			return err
+		}
+		if a.Dexterity, err = t.Int(row, cols.Dexterity); err != nil {
+			return err
+		}
+		if a.Vitality, err = t.Int(row, cols.Vitality); err != nil {
+			return err
+		}
+		if a.Energy, err = t.Int(row, cols.Energy); err != nil {
+			return err
+		}
+		c.Add(faction, a)
+	}
+	return nil
+}
+
+// Add : 加入一个门派的初始属性，已存在时覆盖
+func (c *AttributeTable) Add(faction gmstruct.Faction, a Attributes) {
+	c.bases[faction] = a
+}
+
+// BaseAttributes : 获取门派的初始属性
+func (c *AttributeTable) BaseAttributes(faction gmstruct.Faction) (Attributes, bool) {
+	a, ok := c.bases[faction]
+	return a, ok
+}
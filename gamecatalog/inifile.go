@@ -0,0 +1,153 @@
+package gamecatalog
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// IniFile : 游戏配置INI文件，GBK编码
+type IniFile struct {
+	Name     string                       // 文件名
+	sections map[string]map[string]string // 段名 -> 键 -> 值
+	order    []string                     // 段名出现顺序
+}
+
+// ParseIniFile : 解析GBK编码的INI文件，支持';'和'//'注释
+// 行首的注释标记整行为注释，行中前面有空白的注释标记到行尾为注释
+func ParseIniFile(name string, r io.Reader) (*IniFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text := gmstruct.DecodeGBK(data)
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	f := &IniFile{Name: name, sections: make(map[string]map[string]string)}
+	section := ""
+	f.addSection(section)
+
+	for n, line := range strings.Split(text, "\n") {
+		line = stripIniComment(line)
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("gamecatalog: ini file %s line %d : bad section %q", name, n+1, line)
+			}
+			section = strings.TrimSpace(line[1:end])
+			f.addSection(section)
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("gamecatalog: ini file %s line %d : bad key value %q", name, n+1, line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		f.sections[section][key] = strings.TrimSpace(line[eq+1:])
+	}
+
+	return f, nil
+}
+
+// stripIniComment : 去掉行尾注释和首尾空白，注释标记前面必须是空白，避免截断值中的';'和'//'
+func stripIniComment(line string) string {
+	line = strings.TrimSpace(line)
+	for i := 1; i < len(line); i++ {
+		if line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+		if line[i] == ';' || strings.HasPrefix(line[i:], "//") {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// LoadIniFile : 从文件系统中加载INI文件
+func LoadIniFile(fsys fs.FS, name string) (*IniFile, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseIniFile(name, file)
+}
+
+func (f *IniFile) addSection(section string) {
+	if _, ok := f.sections[section]; ok {
+		return
+	}
+	f.sections[section] = make(map[string]string)
+	if section != "" {
+		f.order = append(f.order, section)
+	}
+}
+
+// Sections : 获取所有段名，按出现顺序排列
+func (f *IniFile) Sections() []string {
+	return append([]string(nil), f.order...)
+}
+
+// HasSection : 是否存在指定段
+func (f *IniFile) HasSection(section string) bool {
+	_, ok := f.sections[section]
+	return ok
+}
+
+// Keys : 获取指定段的所有键
+func (f *IniFile) Keys(section string) []string {
+	var keys []string
+	for key := range f.sections[section] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// String : 获取指定段中键对应的字符串
+func (f *IniFile) String(section, key string) (string, bool) {
+	v, ok := f.sections[section][key]
+	return v, ok
+}
+
+// Int : 获取指定段中键对应的整数，键不存在或格式错误时返回默认值
+func (f *IniFile) Int(section, key string, def int) int {
+	s, ok := f.String(section, key)
+	if !ok {
+		return def
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Ints : 获取指定段中键对应的以','分隔的整数列表
+func (f *IniFile) Ints(section, key string) ([]int, bool) {
+	s, ok := f.String(section, key)
+	if !ok {
+		return nil, false
+	}
+
+	var ret []int
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, false
+		}
+		ret = append(ret, v)
+	}
+	return ret, true
+}
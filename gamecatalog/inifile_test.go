@@ -0,0 +1,56 @@
+package gamecatalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIniFile(t *testing.T) {
+	text := "; comment\r\n" +
+		"[Main]\r\n" +
+		"Count = 3 ; inline comment\r\n" +
+		"Path=maps\\a;b\r\n" +
+		"Url=http://host/x // inline comment\r\n" +
+		"List=1, 2,3\r\n" +
+		"// comment\r\n" +
+		"[Other]\n" +
+		"Bad=x\n"
+	f, err := ParseIniFile("test.ini", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sections := f.Sections(); len(sections) != 2 || sections[0] != "Main" || sections[1] != "Other" {
+		t.Fatalf("sections = %v", sections)
+	}
+	if f.Int("Main", "Count", 0) != 3 {
+		t.Fatalf("Count = %d", f.Int("Main", "Count", 0))
+	}
+
+	// 前面没有空白的';'和'//'属于值的一部分
+	if v, _ := f.String("Main", "Path"); v != "maps\\a;b" {
+		t.Fatalf("Path = %q", v)
+	}
+	if v, _ := f.String("Main", "Url"); v != "http://host/x" {
+		t.Fatalf("Url = %q", v)
+	}
+
+	if list, ok := f.Ints("Main", "List"); !ok || len(list) != 3 || list[2] != 3 {
+		t.Fatalf("List = %v, %v", list, ok)
+	}
+	if _, ok := f.Ints("Other", "Bad"); ok {
+		t.Fatal("bad int list accepted")
+	}
+	if f.Int("Other", "Bad", -1) != -1 || f.Int("Other", "Missing", -2) != -2 {
+		t.Fatal("Int default not used")
+	}
+}
+
+func TestParseIniFileErrors(t *testing.T) {
+	if _, err := ParseIniFile("a.ini", strings.NewReader("[Main\n")); err == nil {
+		t.Fatal("bad section accepted")
+	}
+	if _, err := ParseIniFile("b.ini", strings.NewReader("[Main]\nnoequal\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("bad key value error = %v", err)
+	}
+}
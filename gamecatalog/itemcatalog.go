@@ -0,0 +1,251 @@
+package gamecatalog
+
+import (
+	"fmt"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// 物品大类(Genre)定义
+const (
+	ItemGenreEquipment = 0 // 装备
+	ItemGenreMedicine  = 1 // 药品
+	ItemGenreMine      = 2 // 矿石
+	ItemGenreMaterial  = 3 // 材料
+	ItemGenreQuest     = 4 // 任务物品
+	ItemGenreTownPort  = 5 // 回城符
+)
+
+// ItemKey : 物品类型标识(G, D, P)
+type ItemKey struct {
+	Genre          int // 物品大类
+	DetailType     int // 物品小类
+	ParticularType int // 物品具体类型
+}
+
+// String : 格式化物品类型标识
+func (k ItemKey) String() string {
+	return fmt.Sprintf("(%d, %d, %d)", k.Genre, k.DetailType, k.ParticularType)
+}
+
+// ItemKeyOf : 获取物品数据的类型标识
+func ItemKeyOf(std *gmstruct.ItemDataStd) ItemKey {
+	return ItemKey{
+		Genre:          int(std.ClassCode & 0x0000FFFF),
+		DetailType:     int(std.DetailType),
+		ParticularType: int(std.ParticularType),
+	}
+}
+
+// ItemInfo : 物品配置信息
+type ItemInfo struct {
+	ItemKey
+	Name         string          // 物品名称
+	Desc         string          // 物品说明
+	RequireLevel int             // 需求等级
+	Series       gmstruct.Series // 五行，-1表示无五行要求
+	MaxStack     int             // 最大叠放数量，1表示不可叠放
+	Width        int             // 物品宽度（格子数）
+	Height       int             // 物品高度（格子数）
+}
+
+// Stackable : 物品是否可以叠放
+func (i *ItemInfo) Stackable() bool {
+	return i.MaxStack > 1
+}
+
+// ItemCatalog : 物品配置查询服务
+type ItemCatalog interface {
+	LookupItem(key ItemKey) (*ItemInfo, bool)
+}
+
+// LookupItemData : 查询物品数据对应的物品配置信息
+func LookupItemData(c ItemCatalog, item *gmstruct.ItemData) (*ItemInfo, bool) {
+	if c == nil || !item.HasStandard {
+		return nil, false
+	}
+	return c.LookupItem(ItemKeyOf(&item.Standard))
+}
+
+// ItemName : 获取物品名称，物品配置未知时返回空字符串
+func ItemName(c ItemCatalog, item *gmstruct.ItemData) string {
+	if info, ok := LookupItemData(c, item); ok {
+		return info.Name
+	}
+	return ""
+}
+
+// ItemColumns : 物品配置Tab文件的列名
+type ItemColumns struct {
+	Name           string // 物品名称
+	Desc           string // 物品说明
+	Genre          string // 物品大类，为空时使用ItemTableSpec.Genre
+	DetailType     string // 物品小类，为空时使用ItemTableSpec.DetailType
+	ParticularType string // 物品具体类型，为空时使用行号
+	RequireLevel   string // 需求等级
+	Series         string // 五行
+	MaxStack       string // 最大叠放数量
+	Width          string // 物品宽度
+	Height         string // 物品高度
+}
+
+// DefaultItemColumns : 物品配置Tab文件的默认列名
+func DefaultItemColumns() ItemColumns {
+	return ItemColumns{
+		Name:         "Name",
+		Desc:         "Intro",
+		RequireLevel: "Level",
+		Series:       "Series",
+		MaxStack:     "Stack",
+		Width:        "Width",
+		Height:       "Height",
+	}
+}
+
+// ItemTableSpec : 物品配置Tab文件说明
+type ItemTableSpec struct {
+	File       string      // Tab文件路径（相对于配置目录）
+	Genre      int         // 物品大类，Columns.Genre为空时使用
+	DetailType int         // 物品小类，Columns.DetailType为空时使用
+	Columns    ItemColumns // 列名
+}
+
+// DefaultItemTableSpecs : 默认的物品配置Tab文件列表
+func DefaultItemTableSpecs() []ItemTableSpec {
+	equip := func(file string, detail int) ItemTableSpec {
+		return ItemTableSpec{File: file, Genre: ItemGenreEquipment, DetailType: detail, Columns: DefaultItemColumns()}
+	}
+	withDetail := func(file string, genre int) ItemTableSpec {
+		columns := DefaultItemColumns()
+		columns.DetailType = "DetailType"
+		columns.ParticularType = "ParticularType"
+		return ItemTableSpec{File: file, Genre: genre, Columns: columns}
+	}
+
+	return []ItemTableSpec{
+		equip("item/004/meleeweapon.txt", 0),
+		equip("item/004/rangeweapon.txt", 1),
+		equip("item/004/armor.txt", 2),
+		equip("item/004/ring.txt", 3),
+		equip("item/004/amulet.txt", 4),
+		equip("item/004/boot.txt", 5),
+		equip("item/004/belt.txt", 6),
+		equip("item/004/helm.txt", 7),
+		equip("item/004/cuff.txt", 8),
+		equip("item/004/pendant.txt", 9),
+		equip("item/004/horse.txt", 10),
+		withDetail("item/004/potion.txt", ItemGenreMedicine),
+		withDetail("item/004/mine.txt", ItemGenreMine),
+		withDetail("item/004/questkey.txt", ItemGenreQuest),
+		withDetail("item/004/townportal.txt", ItemGenreTownPort),
+	}
+}
+
+// ItemTable : 由物品配置Tab文件构建的物品配置表，同时可作为背包布局的物品尺寸来源
+type ItemTable struct {
+	items map[ItemKey]*ItemInfo
+}
+
+// NewItemTable : 创建一个空的物品配置表
+func NewItemTable() *ItemTable {
+	return &ItemTable{items: make(map[ItemKey]*ItemInfo)}
+}
+
+// LoadItemTable : 根据物品配置Tab文件列表加载物品配置表，specs为空时使用默认列表
+func LoadItemTable(s *Settings, specs []ItemTableSpec) (*ItemTable, error) {
+	if specs == nil {
+		specs = DefaultItemTableSpecs()
+	}
+
+	table := NewItemTable()
+	for _, spec := range specs {
+		t, err := s.TabFile(spec.File)
+		if err != nil {
+			return nil, err
+		}
+		if err := table.AddTabFile(t, spec); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// AddTabFile : 将物品配置Tab文件中的物品加入配置表
+func (c *ItemTable) AddTabFile(t *TabFile, spec ItemTableSpec) error {
+	cols := spec.Columns
+	if err := t.RequireColumns(cols.Name); err != nil {
+		return err
+	}
+
+	optional := func(row int, col string, def int) (int, error) {
+		if col == "" || !t.HasColumn(col) || t.String(row, col) == "" {
+			return def, nil
+		}
+		return t.Int(row, col)
+	}
+
+	for row := 0; row < t.RowCount(); row++ {
+		info := &ItemInfo{
+			Name:   t.String(row, cols.Name),
+			Series: -1,
+		}
+		if cols.Desc != "" {
+			info.Desc = t.String(row, cols.Desc)
+		}
+
+		var err error
+		var series int
+		if info.Genre, err = optional(row, cols.Genre, spec.Genre); err != nil {
+			return err
+		}
+		if info.DetailType, err = optional(row, cols.DetailType, spec.DetailType); err != nil {
+			return err
+		}
+		if info.ParticularType, err = optional(row, cols.ParticularType, row); err != nil {
+			return err
+		}
+		if info.RequireLevel, err = optional(row, cols.RequireLevel, 0); err != nil {
+			return err
+		}
+		if series, err = optional(row, cols.Series, -1); err != nil {
+			return err
+		}
+		if info.MaxStack, err = optional(row, cols.MaxStack, 1); err != nil {
+			return err
+		}
+		if info.Width, err = optional(row, cols.Width, 1); err != nil {
+			return err
+		}
+		if info.Height, err = optional(row, cols.Height, 1); err != nil {
+			return err
+		}
+		info.Series = gmstruct.Series(series)
+
+		c.Add(info)
+	}
+	return nil
+}
+
+// Add : 加入一条物品配置信息，已存在时覆盖
+func (c *ItemTable) Add(info *ItemInfo) {
+	c.items[info.ItemKey] = info
+}
+
+// Len : 获取物品配置条目数
+func (c *ItemTable) Len() int {
+	return len(c.items)
+}
+
+// LookupItem : 查询物品配置信息
+func (c *ItemTable) LookupItem(key ItemKey) (*ItemInfo, bool) {
+	info, ok := c.items[key]
+	return info, ok
+}
+
+// ItemSize : 获取物品占用的格子尺寸，物品配置未知时返回1x1
+func (c *ItemTable) ItemSize(item *gmstruct.ItemData) (width, height int) {
+	if info, ok := LookupItemData(c, item); ok {
+		return info.Width, info.Height
+	}
+	return 1, 1
+}
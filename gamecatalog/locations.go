@@ -0,0 +1,113 @@
+package gamecatalog
+
+import (
+	"fmt"
+)
+
+// DefaultSafeLocationFile : 安全传送点INI文件的默认路径
+//
+// 配置文件由我们自行维护，每个传送点一段，段名为传送点名称：
+//
+//	[凤翔]
+//	MapId=1
+//	X=50848
+//	Y=102400
+//	RevivalId=1       ; 重生点地图ID，默认与MapId相同
+//	RevivalPoint=0    ; 重生点ID
+const DefaultSafeLocationFile = "safelocations.ini"
+
+// SafeLocation : 用于修复卡地图角色的安全位置
+type SafeLocation struct {
+	Name         string // 传送点名称
+	MapID        int    // 地图ID
+	X            int32  // 地图X坐标（像素坐标）
+	Y            int32  // 地图Y坐标（像素坐标）
+	RevivalID    int    // 重生点地图ID
+	RevivalPoint int32  // 重生点ID
+}
+
+// Validate : 根据地图配置检查传送点是否有效，c为空时不检查
+func (l *SafeLocation) Validate(c MapCatalog) error {
+	if c == nil {
+		return nil
+	}
+
+	info, ok := c.LookupMap(l.MapID)
+	if !ok {
+		return fmt.Errorf("gamecatalog: safe location %s : unknown map %d", l.Name, l.MapID)
+	}
+	if info.Instanced {
+		return fmt.Errorf("gamecatalog: safe location %s : map %d %s is an instanced map", l.Name, l.MapID, info.Name)
+	}
+	if !info.Contains(l.X, l.Y) {
+		return fmt.Errorf("gamecatalog: safe location %s : position (%d, %d) out of map %d %s", l.Name, l.X, l.Y, l.MapID, info.Name)
+	}
+	if _, ok := c.LookupMap(l.RevivalID); !ok {
+		return fmt.Errorf("gamecatalog: safe location %s : unknown revival map %d", l.Name, l.RevivalID)
+	}
+	return nil
+}
+
+// SafeLocations : 安全传送点列表，按配置文件中的顺序排列
+type SafeLocations []SafeLocation
+
+// LoadSafeLocations : 从配置目录加载安全传送点列表
+func LoadSafeLocations(s *Settings, file string) (SafeLocations, error) {
+	f, err := s.IniFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeLocations(f)
+}
+
+// ParseSafeLocations : 解析安全传送点INI文件
+func ParseSafeLocations(f *IniFile) (SafeLocations, error) {
+	var locations SafeLocations
+
+	for _, section := range f.Sections() {
+		mapID := f.Int(section, "MapId", -1)
+		if mapID < 0 {
+			return nil, fmt.Errorf("gamecatalog: safe location file %s : missing MapId in [%s]", f.Name, section)
+		}
+
+		locations = append(locations, SafeLocation{
+			Name:         section,
+			MapID:        mapID,
+			X:            int32(f.Int(section, "X", 0)),
+			Y:            int32(f.Int(section, "Y", 0)),
+			RevivalID:    f.Int(section, "RevivalId", mapID),
+			RevivalPoint: int32(f.Int(section, "RevivalPoint", 0)),
+		})
+	}
+
+	return locations, nil
+}
+
+// Find : 根据名称查找传送点
+func (l SafeLocations) Find(name string) (*SafeLocation, bool) {
+	for i := range l {
+		if l[i].Name == name {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// FindMap : 查找指定地图上的第一个传送点
+func (l SafeLocations) FindMap(mapID int) (*SafeLocation, bool) {
+	for i := range l {
+		if l[i].MapID == mapID {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// Names : 获取所有传送点名称
+func (l SafeLocations) Names() []string {
+	names := make([]string, 0, len(l))
+	for i := range l {
+		names = append(names, l[i].Name)
+	}
+	return names
+}
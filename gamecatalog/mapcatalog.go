@@ -0,0 +1,229 @@
+package gamecatalog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMapListFile : 服务端地图列表INI文件的默认路径
+const DefaultMapListFile = "maplist.ini"
+
+// DefaultMapListSection : 地图列表INI文件中记录地图的段名
+const DefaultMapListSection = "List"
+
+// MapInfo : 地图配置信息
+type MapInfo struct {
+	ID        int    // 地图ID
+	Name      string // 地图名称
+	Path      string // 地图资源路径
+	MinX      int32  // 地图坐标范围（像素坐标），全为0表示范围未知
+	MinY      int32
+	MaxX      int32
+	MaxY      int32
+	Instanced bool // 是否为副本地图，副本地图不能作为登出地图和重生点
+}
+
+// HasBounds : 是否配置了地图坐标范围
+func (m *MapInfo) HasBounds() bool {
+	return m.MinX != 0 || m.MinY != 0 || m.MaxX != 0 || m.MaxY != 0
+}
+
+// Contains : 坐标是否位于地图范围内，范围未知时总是返回true
+func (m *MapInfo) Contains(x, y int32) bool {
+	if !m.HasBounds() {
+		return true
+	}
+	return x >= m.MinX && x <= m.MaxX && y >= m.MinY && y <= m.MaxY
+}
+
+// MapCatalog : 地图配置查询服务
+type MapCatalog interface {
+	LookupMap(id int) (*MapInfo, bool)
+}
+
+// MapName : 获取地图名称，地图配置未知时返回空字符串
+func MapName(c MapCatalog, id int) string {
+	if c == nil {
+		return ""
+	}
+	if info, ok := c.LookupMap(id); ok {
+		return info.Name
+	}
+	return ""
+}
+
+// MapColumns : 地图范围Tab文件的列名
+type MapColumns struct {
+	ID        string // 地图ID
+	Name      string // 地图名称，为空时不覆盖地图列表中的名称
+	MinX      string // 地图坐标范围
+	MinY      string
+	MaxX      string
+	MaxY      string
+	Instanced string // 是否为副本地图
+}
+
+// DefaultMapColumns : 地图范围Tab文件的默认列名
+func DefaultMapColumns() MapColumns {
+	return MapColumns{
+		ID:        "MapId",
+		Name:      "MapName",
+		MinX:      "MinX",
+		MinY:      "MinY",
+		MaxX:      "MaxX",
+		MaxY:      "MaxY",
+		Instanced: "IsCopy",
+	}
+}
+
+// MapTable : 由地图列表INI文件和地图范围Tab文件构建的地图配置表
+type MapTable struct {
+	maps     map[int]*MapInfo
+	unlisted []int // 地图范围Tab文件中不在地图列表中的地图ID
+}
+
+// NewMapTable : 创建一个空的地图配置表
+func NewMapTable() *MapTable {
+	return &MapTable{maps: make(map[int]*MapInfo)}
+}
+
+// LoadMapTable : 从配置目录加载地图列表，boundsFile不为空时同时加载地图范围
+func LoadMapTable(s *Settings, listFile, boundsFile string) (*MapTable, error) {
+	table := NewMapTable()
+
+	f, err := s.IniFile(listFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := table.AddMapList(f, DefaultMapListSection); err != nil {
+		return nil, err
+	}
+
+	if boundsFile == "" {
+		return table, nil
+	}
+
+	t, err := s.TabFile(boundsFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := table.AddTabFile(t, DefaultMapColumns()); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// AddMapList : 加入地图列表INI文件中的地图，格式为"地图ID=资源路径"和"地图ID_name=地图名称"
+func (c *MapTable) AddMapList(f *IniFile, section string) error {
+	if !f.HasSection(section) {
+		return fmt.Errorf("gamecatalog: map list %s : missing section [%s]", f.Name, section)
+	}
+
+	for _, key := range f.Keys(section) {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			// 跳过Count及"地图ID_xxx"等附加属性
+			continue
+		}
+
+		info := c.mapInfo(id)
+		info.Path, _ = f.String(section, key)
+		if name, ok := f.String(section, key+"_name"); ok {
+			info.Name = name
+		}
+		if info.Name == "" {
+			info.Name = mapNameOfPath(info.Path)
+		}
+	}
+	return nil
+}
+
+// mapNameOfPath : 使用地图资源路径的最后一段作为地图名称
+func mapNameOfPath(path string) string {
+	path = strings.ReplaceAll(path, "\\", "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// AddTabFile : 将地图范围Tab文件中的地图范围和副本标记加入配置表
+// 只更新地图列表中已有的地图，从地图列表中移除的地图不会被重新加入，跳过的地图ID可以通过Unlisted获取
+func (c *MapTable) AddTabFile(t *TabFile, cols MapColumns) error {
+	if err := t.RequireColumns(cols.ID); err != nil {
+		return err
+	}
+
+	for row := 0; row < t.RowCount(); row++ {
+		if t.String(row, cols.ID) == "" {
+			continue
+		}
+
+		id, err := t.Int(row, cols.ID)
+		if err != nil {
+			return err
+		}
+
+		info, ok := c.maps[id]
+		if !ok {
+			c.unlisted = append(c.unlisted, id)
+			continue
+		}
+		if cols.Name != "" {
+			if name := t.String(row, cols.Name); name != "" {
+				info.Name = name
+			}
+		}
+		info.MinX = int32(t.IntDefault(row, cols.MinX, int(info.MinX)))
+		info.MinY = int32(t.IntDefault(row, cols.MinY, int(info.MinY)))
+		info.MaxX = int32(t.IntDefault(row, cols.MaxX, int(info.MaxX)))
+		info.MaxY = int32(t.IntDefault(row, cols.MaxY, int(info.MaxY)))
+		if cols.Instanced != "" && t.HasColumn(cols.Instanced) {
+			info.Instanced = t.IntDefault(row, cols.Instanced, 0) != 0
+		}
+	}
+	return nil
+}
+
+// Unlisted : 获取地图范围Tab文件中不在地图列表中而被跳过的地图ID
+func (c *MapTable) Unlisted() []int {
+	return c.unlisted
+}
+
+// mapInfo : 获取地图配置信息，不存在时创建
+func (c *MapTable) mapInfo(id int) *MapInfo {
+	info, ok := c.maps[id]
+	if !ok {
+		info = &MapInfo{ID: id}
+		c.maps[id] = info
+	}
+	return info
+}
+
+// Add : 加入一条地图配置信息，已存在时覆盖
+func (c *MapTable) Add(info *MapInfo) {
+	c.maps[info.ID] = info
+}
+
+// Len : 获取地图配置条目数
+func (c *MapTable) Len() int {
+	return len(c.maps)
+}
+
+// LookupMap : 查询地图配置信息
+func (c *MapTable) LookupMap(id int) (*MapInfo, bool) {
+	info, ok := c.maps[id]
+	return info, ok
+}
+
+// IDs : 获取所有地图ID，按升序排列
+func (c *MapTable) IDs() []int {
+	ids := make([]int, 0, len(c.maps))
+	for id := range c.maps {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,40 @@
+package gamecatalog
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMapTableSkipsUnlistedMaps(t *testing.T) {
+	fsys := fstest.MapFS{
+		"maplist.ini": {Data: []byte("[List]\nCount=2\n1=maps\\fengxiang\n1_name=凤翔\n2=maps\\chengdu\n")},
+		"mapbounds.txt": {Data: []byte("MapId\tMinX\tMinY\tMaxX\tMaxY\tIsCopy\n" +
+			"1\t0\t0\t1000\t2000\t0\n" +
+			"2\t0\t0\t500\t500\t1\n" +
+			"3\t0\t0\t500\t500\t0\n")},
+	}
+
+	table, err := LoadMapTable(NewSettings(fsys), "maplist.ini", "mapbounds.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 2 {
+		t.Fatalf("map count = %d, want 2", table.Len())
+	}
+
+	// 地图列表中没有的地图不能被地图范围文件重新加入
+	if _, ok := table.LookupMap(3); ok {
+		t.Fatal("unlisted map 3 added")
+	}
+	if unlisted := table.Unlisted(); len(unlisted) != 1 || unlisted[0] != 3 {
+		t.Fatalf("unlisted = %v", unlisted)
+	}
+
+	info, ok := table.LookupMap(1)
+	if !ok || info.Name != "凤翔" || info.MaxY != 2000 || info.Instanced {
+		t.Fatalf("map 1 = %+v", info)
+	}
+	if info, _ = table.LookupMap(2); info.Name != "chengdu" || !info.Instanced {
+		t.Fatalf("map 2 = %+v", info)
+	}
+}
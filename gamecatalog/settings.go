@@ -0,0 +1,170 @@
+package gamecatalog
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// Settings : 游戏服务端配置目录，按需加载并缓存Tab文件和INI文件
+type Settings struct {
+	fsys     fs.FS
+	mutex    sync.Mutex
+	tabFiles map[string]*TabFile
+	iniFiles map[string]*IniFile
+}
+
+// NewSettings : 根据文件系统创建配置目录对象
+func NewSettings(fsys fs.FS) *Settings {
+	return &Settings{
+		fsys:     fsys,
+		tabFiles: make(map[string]*TabFile),
+		iniFiles: make(map[string]*IniFile),
+	}
+}
+
+// OpenSettingsDir : 根据本地目录创建配置目录对象
+func OpenSettingsDir(dir string) *Settings {
+	return NewSettings(os.DirFS(dir))
+}
+
+// cleanName : 将服务端脚本中常见的"\settings\xxx.txt"格式路径转换为fs.FS路径
+func cleanName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = strings.TrimLeft(name, "/")
+	return path.Clean(name)
+}
+
+// TabFile : 加载Tab文件，已加载的文件直接返回缓存
+func (s *Settings) TabFile(name string) (*TabFile, error) {
+	name = cleanName(name)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if t, ok := s.tabFiles[name]; ok {
+		return t, nil
+	}
+
+	t, err := LoadTabFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	s.tabFiles[name] = t
+	return t, nil
+}
+
+// IniFile : 加载INI文件，已加载的文件直接返回缓存
+func (s *Settings) IniFile(name string) (*IniFile, error) {
+	name = cleanName(name)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if f, ok := s.iniFiles[name]; ok {
+		return f, nil
+	}
+
+	f, err := LoadIniFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	s.iniFiles[name] = f
+	return f, nil
+}
+
+// NameLookup : ID到名称的查找服务
+type NameLookup interface {
+	Name(id int) (string, bool)
+}
+
+// NameTable : 由Tab文件构建的ID到名称查找表
+type NameTable struct {
+	names map[int]string
+}
+
+// NewNameTable : 根据Tab文件中的ID列和名称列构建查找表
+func NewNameTable(t *TabFile, idCol, nameCol string) (*NameTable, error) {
+	if err := t.RequireColumns(idCol, nameCol); err != nil {
+		return nil, err
+	}
+
+	table := &NameTable{names: make(map[int]string)}
+	for row := 0; row < t.RowCount(); row++ {
+		if t.String(row, idCol) == "" {
+			continue
+		}
+
+		id, err := t.Int(row, idCol)
+		if err != nil {
+			return nil, err
+		}
+		table.names[id] = t.String(row, nameCol)
+	}
+	return table, nil
+}
+
+// Name : 获取ID对应的名称
+func (n *NameTable) Name(id int) (string, bool) {
+	name, ok := n.names[id]
+	return name, ok
+}
+
+// Len : 获取查找表条目数
+func (n *NameTable) Len() int {
+	return len(n.names)
+}
+
+// IDs : 获取所有ID，按升序排列
+func (n *NameTable) IDs() []int {
+	var ids []int
+	for id := range n.names {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// FormatName : 格式化ID及其名称，名称未知时只输出ID
+func FormatName(lookup NameLookup, id int) string {
+	if lookup != nil {
+		if name, ok := lookup.Name(id); ok && name != "" {
+			return fmt.Sprintf("%s(%d)", name, id)
+		}
+	}
+	return fmt.Sprintf("%d", id)
+}
+
+// LoadLevelExpTable : 根据Tab文件中的等级列和经验列构建等级经验表
+func LoadLevelExpTable(t *TabFile, levelCol, expCol string) (gmstruct.LevelExpTable, error) {
+	if err := t.RequireColumns(levelCol, expCol); err != nil {
+		return nil, err
+	}
+
+	var table gmstruct.LevelExpTable
+	for row := 0; row < t.RowCount(); row++ {
+		level, err := t.Int(row, levelCol)
+		if err != nil {
+			return nil, err
+		}
+		exp, err := t.Int64(row, expCol)
+		if err != nil {
+			return nil, err
+		}
+		if level < 0 || level > 0xFFFF {
+			return nil, fmt.Errorf("gamecatalog: %s line %d : bad level %d", t.Name, t.Line(row), level)
+		}
+
+		for len(table) <= level {
+			table = append(table, 0)
+		}
+		table[level] = exp
+	}
+	return table, nil
+}
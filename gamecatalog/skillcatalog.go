@@ -0,0 +1,178 @@
+package gamecatalog
+
+import (
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// SkillInfo : 技能配置信息
+type SkillInfo struct {
+	ID       int              // 技能ID
+	Name     string           // 技能名称
+	Faction  gmstruct.Faction // 所属门派，FactionNone表示通用技能
+	MaxLevel int              // 技能最大等级，0表示不限制
+	UsePoint bool             // 技能升级是否消耗技能点
+}
+
+// SkillCatalog : 技能配置查询服务
+type SkillCatalog interface {
+	LookupSkill(id int) (*SkillInfo, bool)
+}
+
+// SkillName : 获取技能名称，技能配置未知时返回空字符串
+func SkillName(c SkillCatalog, id int) string {
+	if c == nil {
+		return ""
+	}
+	if info, ok := c.LookupSkill(id); ok {
+		return info.Name
+	}
+	return ""
+}
+
+// SkillColumns : 技能配置Tab文件的列名
+type SkillColumns struct {
+	ID       string // 技能ID
+	Name     string // 技能名称
+	Faction  string // 所属门派，-1表示通用技能
+	MaxLevel string // 技能最大等级
+	UsePoint string // 技能升级是否消耗技能点，为空时门派技能消耗、通用技能不消耗
+}
+
+// DefaultSkillColumns : 技能配置Tab文件的默认列名
+func DefaultSkillColumns() SkillColumns {
+	return SkillColumns{
+		ID:       "SkillId",
+		Name:     "SkillName",
+		Faction:  "FactionId",
+		MaxLevel: "MaxLevel",
+	}
+}
+
+// DefaultSkillFile : 技能配置Tab文件的默认路径
+const DefaultSkillFile = "skills.txt"
+
+// SkillTable : 由技能配置Tab文件构建的技能配置表
+type SkillTable struct {
+	skills map[int]*SkillInfo
+}
+
+// NewSkillTable : 创建一个空的技能配置表
+func NewSkillTable() *SkillTable {
+	return &SkillTable{skills: make(map[int]*SkillInfo)}
+}
+
+// LoadSkillTable : 从配置目录加载技能配置表
+func LoadSkillTable(s *Settings, file string, cols SkillColumns) (*SkillTable, error) {
+	t, err := s.TabFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	table := NewSkillTable()
+	if err := table.AddTabFile(t, cols); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// AddTabFile : 将技能配置Tab文件中的技能加入配置表
+func (c *SkillTable) AddTabFile(t *TabFile, cols SkillColumns) error {
+	if err := t.RequireColumns(cols.ID, cols.Name); err != nil {
+		return err
+	}
+
+	for row := 0; row < t.RowCount(); row++ {
+		if t.String(row, cols.ID) == "" {
+			continue
+		}
+
+		id, err := t.Int(row, cols.ID)
+		if err != nil {
+			return err
+		}
+
+		info := &SkillInfo{ID: id, Name: t.String(row, cols.Name), Faction: gmstruct.FactionNone}
+		if cols.Faction != "" && t.HasColumn(cols.Faction) {
+			if value := t.String(row, cols.Faction); value != "" {
+				if faction, ok := gmstruct.ParseFaction(value); ok {
+					info.Faction = faction
+				}
+			}
+		}
+		if cols.MaxLevel != "" && t.HasColumn(cols.MaxLevel) {
+			if info.MaxLevel, err = t.Int(row, cols.MaxLevel); err != nil {
+				return err
+			}
+		}
+
+		info.UsePoint = info.Faction != gmstruct.FactionNone
+		if cols.UsePoint != "" && t.HasColumn(cols.UsePoint) {
+			info.UsePoint = t.IntDefault(row, cols.UsePoint, 0) != 0
+		}
+
+		c.Add(info)
+	}
+	return nil
+}
+
+// Add : 加入一条技能配置信息，已存在时覆盖
+func (c *SkillTable) Add(info *SkillInfo) {
+	c.skills[info.ID] = info
+}
+
+// Len : 获取技能配置条目数
+func (c *SkillTable) Len() int {
+	return len(c.skills)
+}
+
+// LookupSkill : 查询技能配置信息
+func (c *SkillTable) LookupSkill(id int) (*SkillInfo, bool) {
+	info, ok := c.skills[id]
+	return info, ok
+}
+
+// LevelPointTable : 等级点数表，下标为角色等级，值为达到该等级时累计获得的点数（潜能点或技能点）
+type LevelPointTable []int
+
+// PointsAt : 获取达到指定等级时累计获得的点数，点数表未覆盖该等级时返回false
+func (t LevelPointTable) PointsAt(level int) (int, bool) {
+	if level < 0 || level >= len(t) {
+		return 0, false
+	}
+	return t[level], true
+}
+
+// LoadLevelPointTable : 根据Tab文件中的等级列和点数列构建等级点数表
+// cumulative为false时点数列为每级获得的点数，加载时转换为累计点数
+func LoadLevelPointTable(t *TabFile, levelCol, pointCol string, cumulative bool) (LevelPointTable, error) {
+	if err := t.RequireColumns(levelCol, pointCol); err != nil {
+		return nil, err
+	}
+
+	var table LevelPointTable
+	for row := 0; row < t.RowCount(); row++ {
+		level, err := t.Int(row, levelCol)
+		if err != nil {
+			return nil, err
+		}
+		point, err := t.Int(row, pointCol)
+		if err != nil {
+			return nil, err
+		}
+		if level < 0 || level > 0xFFFF {
+			continue
+		}
+
+		for len(table) <= level {
+			table = append(table, 0)
+		}
+		table[level] = point
+	}
+
+	if !cumulative {
+		for i := 1; i < len(table); i++ {
+			table[i] += table[i-1]
+		}
+	}
+	return table, nil
+}
@@ -0,0 +1,164 @@
+package gamecatalog
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// TabFile : 游戏配置Tab文件，GBK编码，以Tab分隔列，第一行为列名
+type TabFile struct {
+	Name    string         // 文件名
+	Header  []string       // 列名
+	Rows    [][]string     // 数据行（不含列名行）
+	columns map[string]int // 列名索引
+	lines   []int          // 数据行在文件中的行号，从1开始
+}
+
+// ParseTabFile : 解析GBK编码的Tab文件
+func ParseTabFile(name string, r io.Reader) (*TabFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text := gmstruct.DecodeGBK(data)
+	text = strings.TrimPrefix(text, "\ufeff")
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	t := &TabFile{Name: name, columns: make(map[string]int)}
+	for n, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if t.Header == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			t.Header = strings.Split(line, "\t")
+			for i, col := range t.Header {
+				col = strings.TrimSpace(col)
+				t.Header[i] = col
+				if _, ok := t.columns[col]; !ok && col != "" {
+					t.columns[col] = i
+				}
+			}
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		t.Rows = append(t.Rows, strings.Split(line, "\t"))
+		t.lines = append(t.lines, n+1)
+	}
+
+	if t.Header == nil {
+		return nil, fmt.Errorf("gamecatalog: tab file %s has no header", name)
+	}
+	return t, nil
+}
+
+// LoadTabFile : 从文件系统中加载Tab文件
+func LoadTabFile(fsys fs.FS, name string) (*TabFile, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseTabFile(name, f)
+}
+
+// RowCount : 获取数据行数
+func (t *TabFile) RowCount() int {
+	return len(t.Rows)
+}
+
+// Line : 获取数据行在文件中的行号，用于错误信息；不是从文件解析的行按紧跟列名行计算
+func (t *TabFile) Line(row int) int {
+	if row >= 0 && row < len(t.lines) {
+		return t.lines[row]
+	}
+	return row + 2
+}
+
+// HasColumn : 是否存在指定列
+func (t *TabFile) HasColumn(col string) bool {
+	_, ok := t.columns[col]
+	return ok
+}
+
+// Column : 获取列名对应的列索引
+func (t *TabFile) Column(col string) (int, bool) {
+	i, ok := t.columns[col]
+	return i, ok
+}
+
+// String : 获取指定行列的字符串，行列不存在时返回空字符串
+func (t *TabFile) String(row int, col string) string {
+	i, ok := t.columns[col]
+	if !ok || row < 0 || row >= len(t.Rows) || i >= len(t.Rows[row]) {
+		return ""
+	}
+	return strings.TrimSpace(t.Rows[row][i])
+}
+
+// Int : 获取指定行列的整数，内容为空时返回0
+func (t *TabFile) Int(row int, col string) (int, error) {
+	v, err := t.Int64(row, col)
+	return int(v), err
+}
+
+// Int64 : 获取指定行列的64位整数，内容为空时返回0
+func (t *TabFile) Int64(row int, col string) (int64, error) {
+	s := t.String(row, col)
+	if s == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("gamecatalog: %s line %d column %s : %v", t.Name, t.Line(row), col, err)
+	}
+	return v, nil
+}
+
+// Float : 获取指定行列的浮点数，内容为空时返回0
+func (t *TabFile) Float(row int, col string) (float64, error) {
+	s := t.String(row, col)
+	if s == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("gamecatalog: %s line %d column %s : %v", t.Name, t.Line(row), col, err)
+	}
+	return v, nil
+}
+
+// IntDefault : 获取指定行列的整数，内容为空或格式错误时返回默认值
+func (t *TabFile) IntDefault(row int, col string, def int) int {
+	if t.String(row, col) == "" {
+		return def
+	}
+
+	v, err := t.Int(row, col)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// RequireColumns : 检查Tab文件是否包含所有指定列
+func (t *TabFile) RequireColumns(cols ...string) error {
+	for _, col := range cols {
+		if !t.HasColumn(col) {
+			return fmt.Errorf("gamecatalog: tab file %s has no column %s", t.Name, col)
+		}
+	}
+	return nil
+}
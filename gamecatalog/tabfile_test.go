@@ -0,0 +1,52 @@
+package gamecatalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTabFile(t *testing.T) {
+	text := "\r\nId\tName\tValue\r\n1\tfirst\t10\r\n\r\n2\t second \tbad\r\n3\n"
+	tab, err := ParseTabFile("test.txt", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tab.RequireColumns("Id", "Name", "Value"); err != nil {
+		t.Fatal(err)
+	}
+	if tab.RequireColumns("Missing") == nil {
+		t.Fatal("missing column accepted")
+	}
+
+	// 空行不计入数据行，行号按文件中的位置计算
+	if tab.RowCount() != 3 {
+		t.Fatalf("row count = %d, want 3", tab.RowCount())
+	}
+	if tab.Line(0) != 3 || tab.Line(1) != 5 || tab.Line(2) != 6 {
+		t.Fatalf("lines = %d %d %d", tab.Line(0), tab.Line(1), tab.Line(2))
+	}
+
+	if v, err := tab.Int(0, "Value"); err != nil || v != 10 {
+		t.Fatalf("Int = %d, %v", v, err)
+	}
+	if tab.String(1, "Name") != "second" {
+		t.Fatalf("String = %q", tab.String(1, "Name"))
+	}
+	if _, err := tab.Int(1, "Value"); err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("bad int error = %v", err)
+	}
+	if tab.IntDefault(1, "Value", -1) != -1 {
+		t.Fatal("IntDefault ignored bad value")
+	}
+
+	// 缺少的列按空内容处理
+	if v, err := tab.Int(2, "Value"); err != nil || v != 0 {
+		t.Fatalf("short row Int = %d, %v", v, err)
+	}
+}
+
+func TestParseTabFileNoHeader(t *testing.T) {
+	if _, err := ParseTabFile("empty.txt", strings.NewReader("\n \n")); err == nil {
+		t.Fatal("file without header accepted")
+	}
+}
@@ -0,0 +1,286 @@
+package gamecatalog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultTaskFile : 任务变量配置INI文件的默认路径
+//
+// 配置文件由我们自行维护，每个任务变量一段，段名为任务变量ID：
+//
+//	[1024]
+//	Name=主线任务
+//	Group=主线
+//	Desc=主线任务进度
+//	Value.0=未接取                ; 整个变量值的含义
+//	Flag.31=每日奖励已领取         ; 单个标志位的含义
+//	Field.Step=0,8                ; 位域：起始位,位数
+//	Field.Step.3=第三步            ; 位域取值的含义
+const DefaultTaskFile = "taskdef.ini"
+
+// TaskBitField : 任务变量中的位域
+type TaskBitField struct {
+	Name   string         // 位域名称
+	Offset uint           // 起始位
+	Width  uint           // 位数
+	Values map[int]string // 位域取值 -> 含义
+}
+
+// Extract : 从任务变量值中取出位域的值
+func (f *TaskBitField) Extract(value int32) int {
+	if f.Width == 0 || f.Offset >= 32 {
+		return 0
+	}
+
+	mask := uint32(1)<<f.Width - 1
+	if f.Width >= 32 {
+		mask = 0xFFFFFFFF
+	}
+	return int(uint32(value) >> f.Offset & mask)
+}
+
+// Describe : 获取位域值的含义，没有配置时输出为"名称=值"
+func (f *TaskBitField) Describe(value int32) string {
+	v := f.Extract(value)
+	if text, ok := f.Values[v]; ok {
+		return text
+	}
+	return fmt.Sprintf("%s=%d", f.Name, v)
+}
+
+// TaskInfo : 任务变量配置信息
+type TaskInfo struct {
+	ID     int              // 任务变量ID
+	Name   string           // 任务变量名称
+	Group  string           // 所属任务分组
+	Desc   string           // 任务变量说明
+	Values map[int32]string // 整个变量值 -> 含义
+	Flags  map[uint]string  // 标志位 -> 含义
+	Fields []*TaskBitField  // 位域，按起始位排列
+}
+
+// Decode : 将任务变量值解析为可读的含义，多个含义以", "分隔，没有任何配置匹配时返回空字符串
+func (t *TaskInfo) Decode(value int32) string {
+	var parts []string
+
+	if text, ok := t.Values[value]; ok {
+		parts = append(parts, text)
+	}
+
+	for _, field := range t.Fields {
+		parts = append(parts, field.Describe(value))
+	}
+
+	var bits []int
+	for bit := range t.Flags {
+		if bit < 32 && uint32(value)&(1<<bit) != 0 {
+			bits = append(bits, int(bit))
+		}
+	}
+	sort.Ints(bits)
+	for _, bit := range bits {
+		parts = append(parts, t.Flags[uint(bit)])
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// TaskCatalog : 任务变量配置查询服务
+type TaskCatalog interface {
+	LookupTask(id int) (*TaskInfo, bool)
+}
+
+// TaskName : 获取任务变量名称，任务变量配置未知时返回空字符串
+func TaskName(c TaskCatalog, id int) string {
+	if c == nil {
+		return ""
+	}
+	if info, ok := c.LookupTask(id); ok {
+		return info.Name
+	}
+	return ""
+}
+
+// DescribeTask : 获取任务变量的名称及当前值的含义，格式为"名称 : 含义"
+func DescribeTask(c TaskCatalog, id int, value int32) string {
+	if c == nil {
+		return ""
+	}
+
+	info, ok := c.LookupTask(id)
+	if !ok {
+		return ""
+	}
+
+	meaning := info.Decode(value)
+	switch {
+	case info.Name == "":
+		return meaning
+	case meaning == "":
+		return info.Name
+	default:
+		return info.Name + " : " + meaning
+	}
+}
+
+// TaskTable : 由任务变量配置INI文件构建的任务变量配置表
+type TaskTable struct {
+	tasks map[int]*TaskInfo
+}
+
+// NewTaskTable : 创建一个空的任务变量配置表
+func NewTaskTable() *TaskTable {
+	return &TaskTable{tasks: make(map[int]*TaskInfo)}
+}
+
+// LoadTaskTable : 从配置目录加载任务变量配置表
+func LoadTaskTable(s *Settings, file string) (*TaskTable, error) {
+	f, err := s.IniFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	table := NewTaskTable()
+	if err := table.AddIniFile(f); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// AddIniFile : 将任务变量配置INI文件中的任务变量加入配置表
+func (c *TaskTable) AddIniFile(f *IniFile) error {
+	for _, section := range f.Sections() {
+		id, err := strconv.Atoi(section)
+		if err != nil {
+			return fmt.Errorf("gamecatalog: task file %s : bad task id [%s]", f.Name, section)
+		}
+
+		info, err := parseTaskSection(f, section, id)
+		if err != nil {
+			return err
+		}
+		c.Add(info)
+	}
+	return nil
+}
+
+// parseTaskSection : 解析一个任务变量配置段
+func parseTaskSection(f *IniFile, section string, id int) (*TaskInfo, error) {
+	info := &TaskInfo{
+		ID:     id,
+		Values: make(map[int32]string),
+		Flags:  make(map[uint]string),
+	}
+	info.Name, _ = f.String(section, "Name")
+	info.Group, _ = f.String(section, "Group")
+	info.Desc, _ = f.String(section, "Desc")
+
+	bad := func(key string) error {
+		return fmt.Errorf("gamecatalog: task file %s : bad key %q in [%s]", f.Name, key, section)
+	}
+
+	fields := make(map[string]*TaskBitField)
+	fieldValues := make(map[string]map[int]string)
+
+	for _, key := range f.Keys(section) {
+		value, _ := f.String(section, key)
+		parts := strings.Split(key, ".")
+
+		switch {
+		case parts[0] == "Value" && len(parts) == 2:
+			v, err := strconv.ParseInt(parts[1], 0, 32)
+			if err != nil {
+				return nil, bad(key)
+			}
+			info.Values[int32(v)] = value
+
+		case parts[0] == "Flag" && len(parts) == 2:
+			bit, err := strconv.ParseUint(parts[1], 10, 8)
+			if err != nil || bit >= 32 {
+				return nil, bad(key)
+			}
+			info.Flags[uint(bit)] = value
+
+		case parts[0] == "Field" && len(parts) == 2:
+			layout, ok := f.Ints(section, key)
+			if !ok || len(layout) != 2 || layout[0] < 0 || layout[1] <= 0 || layout[0]+layout[1] > 32 {
+				return nil, bad(key)
+			}
+			fields[parts[1]] = &TaskBitField{Name: parts[1], Offset: uint(layout[0]), Width: uint(layout[1])}
+
+		case parts[0] == "Field" && len(parts) == 3:
+			v, err := strconv.Atoi(parts[2])
+			if err != nil {
+				return nil, bad(key)
+			}
+			if fieldValues[parts[1]] == nil {
+				fieldValues[parts[1]] = make(map[int]string)
+			}
+			fieldValues[parts[1]][v] = value
+		}
+	}
+
+	for name, values := range fieldValues {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("gamecatalog: task file %s : values of undefined field %q in [%s]", f.Name, name, section)
+		}
+		field.Values = values
+	}
+
+	for _, field := range fields {
+		info.Fields = append(info.Fields, field)
+	}
+	sort.Slice(info.Fields, func(i, j int) bool {
+		return info.Fields[i].Offset < info.Fields[j].Offset
+	})
+
+	return info, nil
+}
+
+// Add : 加入一条任务变量配置信息，已存在时覆盖
+func (c *TaskTable) Add(info *TaskInfo) {
+	c.tasks[info.ID] = info
+}
+
+// Len : 获取任务变量配置条目数
+func (c *TaskTable) Len() int {
+	return len(c.tasks)
+}
+
+// LookupTask : 查询任务变量配置信息
+func (c *TaskTable) LookupTask(id int) (*TaskInfo, bool) {
+	info, ok := c.tasks[id]
+	return info, ok
+}
+
+// Groups : 获取所有任务分组名称，按名称排序
+func (c *TaskTable) Groups() []string {
+	seen := make(map[string]bool)
+	var groups []string
+	for _, info := range c.tasks {
+		if info.Group != "" && !seen[info.Group] {
+			seen[info.Group] = true
+			groups = append(groups, info.Group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// TasksInGroup : 获取指定分组中的所有任务变量配置，按ID排序
+func (c *TaskTable) TasksInGroup(group string) []*TaskInfo {
+	var tasks []*TaskInfo
+	for _, info := range c.tasks {
+		if info.Group == group {
+			tasks = append(tasks, info)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
@@ -0,0 +1,77 @@
+package gamecatalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func taskTableForTest(t *testing.T, text string) (*TaskTable, error) {
+	f, err := ParseIniFile("taskdef.ini", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTaskTable()
+	return table, table.AddIniFile(f)
+}
+
+func TestTaskTableDecode(t *testing.T) {
+	table, err := taskTableForTest(t, "[1024]\n"+
+		"Name=main\n"+
+		"Group=story\n"+
+		"Value.0=not accepted ; comment\n"+
+		"Flag.31=reward\n"+
+		"Flag.9=visited\n"+
+		"Field.Step=0,8\n"+
+		"Field.Step.3=step three\n"+
+		"Field.Count=16,4\n"+
+		"[1025]\n"+
+		"Name=side\n"+
+		"Group=daily\n"+
+		"[1026]\n"+
+		"Group=story\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 3 {
+		t.Fatalf("task count = %d, want 3", table.Len())
+	}
+
+	if groups := table.Groups(); len(groups) != 2 || groups[0] != "daily" || groups[1] != "story" {
+		t.Fatalf("groups = %v", groups)
+	}
+	if tasks := table.TasksInGroup("story"); len(tasks) != 2 || tasks[0].ID != 1024 || tasks[1].ID != 1026 {
+		t.Fatalf("story tasks = %v", tasks)
+	}
+
+	// 位域按起始位排列，标志位按位序输出
+	value := int32(3 | 1<<9 | 5<<16 | -1<<31)
+	if got, want := DescribeTask(table, 1024, value), "main : step three, Count=5, visited, reward"; got != want {
+		t.Fatalf("describe = %q, want %q", got, want)
+	}
+	if got, want := DescribeTask(table, 1024, 0), "main : not accepted, Step=0, Count=0"; got != want {
+		t.Fatalf("describe = %q, want %q", got, want)
+	}
+	if DescribeTask(table, 1025, 7) != "side" || DescribeTask(table, 9999, 7) != "" {
+		t.Fatal("describe of task without meanings")
+	}
+	if TaskName(nil, 1024) != "" || TaskName(table, 1025) != "side" {
+		t.Fatal("bad task name")
+	}
+}
+
+func TestTaskTableBadKeys(t *testing.T) {
+	bad := []string{
+		"[main]\nName=x\n",
+		"[1]\nFlag.32=x\n",
+		"[1]\nValue.x=x\n",
+		"[1]\nField.Step=30,4\n",
+		"[1]\nField.Step=0\n",
+		"[1]\nField.Step.1=x\n",
+	}
+	for _, text := range bad {
+		if _, err := taskTableForTest(t, text); err == nil {
+			t.Fatalf("bad task file accepted : %q", text)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package gamecatalog
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DefaultTransferFile : 跨服转移映射INI文件的默认路径
+//
+// 配置文件由我们自行维护，描述源服务器到目标服务器的ID映射：
+//
+//	[Tong]
+//	1001=2001         ; 源服帮会ID=目标服帮会ID，未配置的帮会在目标服务器上退出帮会
+//	[Map]
+//	301=401           ; 源服地图ID=目标服地图ID，未配置的地图ID保持不变
+//	[GUID]
+//	Shift=48          ; GUID前缀为GUID右移Shift位的值
+//	1=2               ; 源服GUID前缀=目标服GUID前缀，未配置的前缀保持不变
+const DefaultTransferFile = "transfer.ini"
+
+// DefaultGUIDShift : GUID前缀的默认位移
+const DefaultGUIDShift = 48
+
+// TransferTable : 跨服转移ID映射表
+type TransferTable struct {
+	Tongs        map[uint32]uint32 // 帮会ID映射
+	Maps         map[int]int       // 地图ID映射
+	GUIDShift    uint              // GUID前缀位移
+	GUIDPrefixes map[int64]int64   // GUID前缀映射
+}
+
+// NewTransferTable : 创建一个空的跨服转移ID映射表
+func NewTransferTable() *TransferTable {
+	return &TransferTable{
+		Tongs:        make(map[uint32]uint32),
+		Maps:         make(map[int]int),
+		GUIDShift:    DefaultGUIDShift,
+		GUIDPrefixes: make(map[int64]int64),
+	}
+}
+
+// LoadTransferTable : 从配置目录加载跨服转移ID映射表
+func LoadTransferTable(s *Settings, file string) (*TransferTable, error) {
+	f, err := s.IniFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTransferTable(f)
+}
+
+// ParseTransferTable : 解析跨服转移映射INI文件
+func ParseTransferTable(f *IniFile) (*TransferTable, error) {
+	t := NewTransferTable()
+
+	parse := func(section string, conv func(s string) (int64, error), add func(from, to int64)) error {
+		for _, key := range f.Keys(section) {
+			if section == "GUID" && key == "Shift" {
+				continue
+			}
+
+			value, _ := f.String(section, key)
+			from, err := conv(key)
+			if err != nil {
+				return fmt.Errorf("gamecatalog: transfer file %s : bad key %q in [%s]", f.Name, key, section)
+			}
+			to, err := conv(value)
+			if err != nil {
+				return fmt.Errorf("gamecatalog: transfer file %s : bad value %q of %s in [%s]", f.Name, value, key, section)
+			}
+			add(from, to)
+		}
+		return nil
+	}
+
+	// 帮会ID为uint32，地图ID为int32，GUID前缀为int64
+	tongID := func(s string) (int64, error) {
+		v, err := strconv.ParseUint(s, 10, 32)
+		return int64(v), err
+	}
+	mapID := func(s string) (int64, error) { return strconv.ParseInt(s, 10, 32) }
+	guid := func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }
+
+	if err := parse("Tong", tongID, func(from, to int64) { t.Tongs[uint32(from)] = uint32(to) }); err != nil {
+		return nil, err
+	}
+	if err := parse("Map", mapID, func(from, to int64) { t.Maps[int(from)] = int(to) }); err != nil {
+		return nil, err
+	}
+	if err := parse("GUID", guid, func(from, to int64) { t.GUIDPrefixes[from] = to }); err != nil {
+		return nil, err
+	}
+
+	shift := f.Int("GUID", "Shift", DefaultGUIDShift)
+	if shift <= 0 || shift >= 63 {
+		return nil, fmt.Errorf("gamecatalog: transfer file %s : bad GUID shift %d", f.Name, shift)
+	}
+	t.GUIDShift = uint(shift)
+	return t, nil
+}
+
+// Tong : 获取目标服务器的帮会ID，未配置的帮会返回false
+func (t *TransferTable) Tong(id uint32) (uint32, bool) {
+	to, ok := t.Tongs[id]
+	return to, ok
+}
+
+// Map : 获取目标服务器的地图ID，未配置的地图ID保持不变
+func (t *TransferTable) Map(id int) int {
+	if to, ok := t.Maps[id]; ok {
+		return to
+	}
+	return id
+}
+
+// GUID : 获取目标服务器的GUID，0和未配置前缀的GUID保持不变
+func (t *TransferTable) GUID(guid int64) int64 {
+	if guid == 0 {
+		return 0
+	}
+
+	to, ok := t.GUIDPrefixes[guid>>t.GUIDShift]
+	if !ok {
+		return guid
+	}
+	mask := int64(1)<<t.GUIDShift - 1
+	return to<<t.GUIDShift | guid&mask
+}
+
+// Inverse : 获取反向映射表，用于将角色转回源服务器，映射不是一一对应时返回错误
+func (t *TransferTable) Inverse() (*TransferTable, error) {
+	inv := NewTransferTable()
+	inv.GUIDShift = t.GUIDShift
+
+	for from, to := range t.Tongs {
+		if _, ok := inv.Tongs[to]; ok {
+			return nil, fmt.Errorf("gamecatalog: transfer table maps several tongs to %d", to)
+		}
+		inv.Tongs[to] = from
+	}
+	for from, to := range t.Maps {
+		if _, ok := inv.Maps[to]; ok {
+			return nil, fmt.Errorf("gamecatalog: transfer table maps several maps to %d", to)
+		}
+		inv.Maps[to] = from
+	}
+	for from, to := range t.GUIDPrefixes {
+		if _, ok := inv.GUIDPrefixes[to]; ok {
+			return nil, fmt.Errorf("gamecatalog: transfer table maps several GUID prefixes to %d", to)
+		}
+		inv.GUIDPrefixes[to] = from
+	}
+	return inv, nil
+}
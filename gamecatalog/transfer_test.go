@@ -0,0 +1,78 @@
+package gamecatalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func transferTableForTest(t *testing.T, text string) (*TransferTable, error) {
+	f, err := ParseIniFile("transfer.ini", strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ParseTransferTable(f)
+}
+
+func TestParseTransferTable(t *testing.T) {
+	table, err := transferTableForTest(t, "[Tong]\n"+
+		"1001=2001\n"+
+		"4000000000=7 ; 帮会ID为uint32\n"+
+		"[Map]\n"+
+		"301=401\n"+
+		"[GUID]\n"+
+		"Shift=40\n"+
+		"1=2\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if to, ok := table.Tong(4000000000); !ok || to != 7 {
+		t.Fatalf("tong = %d, %v", to, ok)
+	}
+	if _, ok := table.Tong(1002); ok {
+		t.Fatal("unmapped tong found")
+	}
+	if table.Map(301) != 401 || table.Map(302) != 302 {
+		t.Fatalf("maps = %d, %d", table.Map(301), table.Map(302))
+	}
+
+	// GUID只替换前缀，保留低位
+	if guid := table.GUID(1<<40 | 12345); guid != 2<<40|12345 {
+		t.Fatalf("guid = %#x", guid)
+	}
+	if table.GUID(3<<40|1) != 3<<40|1 || table.GUID(0) != 0 {
+		t.Fatal("unmapped guid changed")
+	}
+
+	inv, err := table.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to, _ := inv.Tong(2001); to != 1001 || inv.Map(401) != 301 || inv.GUID(2<<40|5) != 1<<40|5 {
+		t.Fatal("bad inverse table")
+	}
+}
+
+func TestParseTransferTableErrors(t *testing.T) {
+	bad := []string{
+		"[Tong]\n-1=2\n",
+		"[Tong]\n1=4294967296\n",
+		"[Map]\n301=x\n",
+		"[Map]\n3000000000=1\n",
+		"[GUID]\nShift=63\n",
+		"[GUID]\nShift=0\n",
+	}
+	for _, text := range bad {
+		if _, err := transferTableForTest(t, text); err == nil {
+			t.Fatalf("bad transfer file accepted : %q", text)
+		}
+	}
+
+	table, err := transferTableForTest(t, "[Map]\n1=5\n2=5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Inverse(); err == nil {
+		t.Fatal("inverse of many-to-one table accepted")
+	}
+}
@@ -0,0 +1,254 @@
+package gamedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// DefaultMaxRolesPerAccount : 每个帐号默认允许的最大角色数
+const DefaultMaxRolesPerAccount = 3
+
+// 数据库操作错误
+var (
+	ErrRoleNotFound = errors.New("gamedb: role not found")
+	ErrAccountFull  = errors.New("gamedb: account already has the maximum number of roles")
+	ErrRoleExists   = errors.New("gamedb: role name already exists")
+)
+
+// DSN : 根据数据库连接参数生成MySQL连接字符串
+func DSN(username, password, ip, port, dbName, charset string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=true", username, password, ip, port, dbName, charset)
+}
+
+// RoleTable : 角色表的表名和列名
+type RoleTable struct {
+	Name          string // 表名
+	IDColumn      string // 角色ID列
+	NameColumn    string // 角色名列（GBK）
+	AccountColumn string // 帐号名列（GBK）
+	DataColumn    string // 角色原始二进制数据列
+	TimeColumn    string // 最后修改时间列
+}
+
+// DefaultRoleTable : 角色表的默认表名和列名
+func DefaultRoleTable() RoleTable {
+	return RoleTable{
+		Name:          "role",
+		IDColumn:      "ID",
+		NameColumn:    "RoleName",
+		AccountColumn: "Account",
+		DataColumn:    "RoleData",
+		TimeColumn:    "LastModified",
+	}
+}
+
+// RoleRecord : 角色表中的一条角色记录，角色名和帐号名已转换为UTF-8
+type RoleRecord struct {
+	ID           int64     // 角色ID
+	RoleName     string    // 角色名
+	Account      string    // 帐号名
+	RoleData     []byte    // 角色原始二进制数据
+	LastModified time.Time // 最后修改时间
+}
+
+// RoleDataRewriter : 在事务中改写角色原始二进制数据的函数
+type RoleDataRewriter func(data []byte) ([]byte, error)
+
+// RoleDB : 角色数据库，数据库驱动由调用者导入
+type RoleDB struct {
+	DB                 *sql.DB   // 数据库连接
+	Table              RoleTable // 角色表结构
+	MaxRolesPerAccount int       // 每个帐号允许的最大角色数
+}
+
+// NewRoleDB : 根据数据库连接创建角色数据库对象，使用默认的角色表结构
+func NewRoleDB(db *sql.DB) *RoleDB {
+	return &RoleDB{
+		DB:                 db,
+		Table:              DefaultRoleTable(),
+		MaxRolesPerAccount: DefaultMaxRolesPerAccount,
+	}
+}
+
+// encodeName : 将UTF-8名称转换为数据库中保存的GBK字节串
+func encodeName(what, s string) ([]byte, error) {
+	b, ok := gmstruct.EncodeGBK(s)
+	if !ok {
+		return nil, fmt.Errorf("gamedb: %s %q can not be converted to GBK", what, s)
+	}
+	return b, nil
+}
+
+// queryer : sql.DB和sql.Tx共有的查询接口
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// queryRole : 查询角色记录，forUpdate为true时锁定该行
+func (d *RoleDB) queryRole(ctx context.Context, q queryer, name string, forUpdate bool) (*RoleRecord, error) {
+	gbkName, err := encodeName("role name", name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &d.Table
+	query := fmt.Sprintf("SELECT %s, %s, %s, %s, %s FROM %s WHERE %s = ?",
+		t.IDColumn, t.NameColumn, t.AccountColumn, t.DataColumn, t.TimeColumn, t.Name, t.NameColumn)
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	var record RoleRecord
+	var roleName, account []byte
+	err = q.QueryRowContext(ctx, query, gbkName).Scan(&record.ID, &roleName, &account, &record.RoleData, &record.LastModified)
+	if err == sql.ErrNoRows {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record.RoleName = gmstruct.DecodeGBK(roleName)
+	record.Account = gmstruct.DecodeGBK(account)
+	return &record, nil
+}
+
+// QueryRole : 根据角色名查询角色记录
+func (d *RoleDB) QueryRole(ctx context.Context, name string) (*RoleRecord, error) {
+	return d.queryRole(ctx, d.DB, name, false)
+}
+
+// countRoles : 查询帐号下的角色数，forUpdate为true时锁定这些行
+func (d *RoleDB) countRoles(ctx context.Context, q queryer, account string, forUpdate bool) (int, error) {
+	gbkAccount, err := encodeName("account", account)
+	if err != nil {
+		return 0, err
+	}
+
+	t := &d.Table
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", t.Name, t.AccountColumn)
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	count := 0
+	err = q.QueryRowContext(ctx, query, gbkAccount).Scan(&count)
+	return count, err
+}
+
+// CountRoles : 查询帐号下的角色数
+func (d *RoleDB) CountRoles(ctx context.Context, account string) (int, error) {
+	return d.countRoles(ctx, d.DB, account, false)
+}
+
+// UpdateRoleData : 在事务中改写角色原始二进制数据
+func (d *RoleDB) UpdateRoleData(ctx context.Context, name string, rewrite RoleDataRewriter) error {
+	return d.withTx(ctx, func(tx *sql.Tx) error {
+		record, err := d.queryRole(ctx, tx, name, true)
+		if err != nil {
+			return err
+		}
+
+		data, err := rewrite(record.RoleData)
+		if err != nil {
+			return err
+		}
+
+		t := &d.Table
+		query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", t.Name, t.DataColumn, t.IDColumn)
+		_, err = tx.ExecContext(ctx, query, data, record.ID)
+		return err
+	})
+}
+
+// MoveRoleToAccount : 在同一个事务中改写角色原始二进制数据并修改角色表的帐号字段
+// 目标帐号的角色数已达到上限时返回ErrAccountFull
+func (d *RoleDB) MoveRoleToAccount(ctx context.Context, name, account string, rewrite RoleDataRewriter) error {
+	gbkAccount, err := encodeName("account", account)
+	if err != nil {
+		return err
+	}
+
+	return d.withTx(ctx, func(tx *sql.Tx) error {
+		record, err := d.queryRole(ctx, tx, name, true)
+		if err != nil {
+			return err
+		}
+		if record.Account == account {
+			return fmt.Errorf("gamedb: role %s already belongs to account %s", name, account)
+		}
+
+		count, err := d.countRoles(ctx, tx, account, true)
+		if err != nil {
+			return err
+		}
+		if d.MaxRolesPerAccount > 0 && count >= d.MaxRolesPerAccount {
+			return fmt.Errorf("%w : %s has %d roles", ErrAccountFull, account, count)
+		}
+
+		data, err := rewrite(record.RoleData)
+		if err != nil {
+			return err
+		}
+
+		t := &d.Table
+		query := fmt.Sprintf("UPDATE %s SET %s = ?, %s = ? WHERE %s = ?", t.Name, t.AccountColumn, t.DataColumn, t.IDColumn)
+		_, err = tx.ExecContext(ctx, query, gbkAccount, data, record.ID)
+		return err
+	})
+}
+
+// RenameRoleRecord : 在同一个事务中改写角色原始二进制数据并修改角色表的角色名字段
+// 新角色名已被使用时返回ErrRoleExists
+func (d *RoleDB) RenameRoleRecord(ctx context.Context, name, newName string, rewrite RoleDataRewriter) error {
+	gbkNewName, err := encodeName("role name", newName)
+	if err != nil {
+		return err
+	}
+
+	return d.withTx(ctx, func(tx *sql.Tx) error {
+		record, err := d.queryRole(ctx, tx, name, true)
+		if err != nil {
+			return err
+		}
+
+		// 锁定新角色名，避免并发创建同名角色
+		t := &d.Table
+		count := 0
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ? FOR UPDATE", t.Name, t.NameColumn)
+		if err := tx.QueryRowContext(ctx, query, gbkNewName).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w : %s", ErrRoleExists, newName)
+		}
+
+		data, err := rewrite(record.RoleData)
+		if err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf("UPDATE %s SET %s = ?, %s = ? WHERE %s = ?", t.Name, t.NameColumn, t.DataColumn, t.IDColumn)
+		_, err = tx.ExecContext(ctx, query, gbkNewName, data, record.ID)
+		return err
+	})
+}
+
+// withTx : 在事务中执行fn，fn返回错误时回滚事务
+func (d *RoleDB) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,245 @@
+package gamedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStore : 内存中的角色表，用于测试事务中的读写
+type fakeStore struct {
+	mu        sync.Mutex
+	rows      []map[string]driver.Value // 列名 -> 值
+	commits   int                       // 提交的事务数
+	rollbacks int                       // 回滚的事务数
+}
+
+// fakeConnector : 连接到内存角色表的数据库连接器
+type fakeConnector struct {
+	store *fakeStore
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{store: c.store}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, fmt.Errorf("fake driver : use sql.OpenDB")
+}
+
+// fakeConn : 内存角色表的连接，事务中的修改在提交时才写入角色表
+type fakeConn struct {
+	store   *fakeStore
+	pending []func()
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.pending = nil
+	return &fakeTx{conn: c}, nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx *fakeTx) Commit() error {
+	store := tx.conn.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, apply := range tx.conn.pending {
+		apply()
+	}
+	tx.conn.pending = nil
+	store.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	store := tx.conn.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	tx.conn.pending = nil
+	store.rollbacks++
+	return nil
+}
+
+var (
+	fakeSelect = regexp.MustCompile(`^SELECT (.+) FROM \w+ WHERE (\w+) = \?( FOR UPDATE)?$`)
+	fakeUpdate = regexp.MustCompile(`^UPDATE \w+ SET (.+) WHERE (\w+) = \?$`)
+)
+
+// fakeStmt : 只支持角色数据库使用的单条件SELECT和UPDATE语句
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	m := fakeUpdate.FindStringSubmatch(s.query)
+	if m == nil {
+		return nil, fmt.Errorf("fake driver : unsupported query %q", s.query)
+	}
+
+	cols := strings.Split(m[1], ", ")
+	if len(args) != len(cols)+1 {
+		return nil, fmt.Errorf("fake driver : %d args for %q", len(args), s.query)
+	}
+
+	store := s.conn.store
+	s.conn.pending = append(s.conn.pending, func() {
+		for _, row := range store.rows {
+			if !reflect.DeepEqual(row[m[2]], args[len(cols)]) {
+				continue
+			}
+			for i, col := range cols {
+				row[strings.TrimSuffix(col, " = ?")] = args[i]
+			}
+		}
+	})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	m := fakeSelect.FindStringSubmatch(s.query)
+	if m == nil || len(args) != 1 {
+		return nil, fmt.Errorf("fake driver : unsupported query %q", s.query)
+	}
+
+	store := s.conn.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	cols := strings.Split(m[1], ", ")
+	rows := &fakeRows{cols: cols}
+	count := int64(0)
+	for _, row := range store.rows {
+		if !reflect.DeepEqual(row[m[2]], args[0]) {
+			continue
+		}
+
+		count++
+		var values []driver.Value
+		for _, col := range cols {
+			values = append(values, row[col])
+		}
+		rows.values = append(rows.values, values)
+	}
+
+	if cols[0] == "COUNT(*)" {
+		rows.values = [][]driver.Value{{count}}
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	cols   []string
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.cols
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// add : 加入一条角色记录
+func (s *fakeStore) add(name, account string, data []byte) {
+	s.rows = append(s.rows, map[string]driver.Value{
+		"ID":           int64(len(s.rows) + 1),
+		"RoleName":     []byte(name),
+		"Account":      []byte(account),
+		"RoleData":     data,
+		"LastModified": time.Unix(0, 0),
+	})
+}
+
+// find : 根据角色名查找角色记录
+func (s *fakeStore) find(name string) map[string]driver.Value {
+	for _, row := range s.rows {
+		if string(row["RoleName"].([]byte)) == name {
+			return row
+		}
+	}
+	return nil
+}
+
+// newRoleDBForTest : 创建使用内存角色表的角色数据库
+func newRoleDBForTest(t *testing.T) (*RoleDB, *fakeStore) {
+	store := &fakeStore{}
+	db := sql.OpenDB(&fakeConnector{store: store})
+	t.Cleanup(func() { db.Close() })
+	return NewRoleDB(db), store
+}
+
+func TestUpdateRoleDataRollback(t *testing.T) {
+	d, store := newRoleDBForTest(t)
+	store.add("Role1", "Acc1", []byte("old"))
+	ctx := context.Background()
+
+	err := d.UpdateRoleData(ctx, "Role1", func(data []byte) ([]byte, error) {
+		return nil, fmt.Errorf("broken")
+	})
+	if err == nil {
+		t.Fatal("rewrite error not returned")
+	}
+	if store.rollbacks != 1 || store.commits != 0 {
+		t.Fatalf("commits = %d, rollbacks = %d", store.commits, store.rollbacks)
+	}
+
+	err = d.UpdateRoleData(ctx, "Role1", func(data []byte) ([]byte, error) {
+		return append(data, "-new"...), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record, err := d.QueryRole(ctx, "Role1"); err != nil || string(record.RoleData) != "old-new" {
+		t.Fatalf("record = %+v, %v", record, err)
+	}
+
+	if _, err := d.QueryRole(ctx, "Missing"); err != ErrRoleNotFound {
+		t.Fatalf("missing role error = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package gamedb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/heartchord/jxonline/gameencoder"
+)
+
+// discardLog : 不输出解码日志
+func discardLog(format string, a ...interface{}) (n int, err error) {
+	return 0, nil
+}
+
+// DecodeRole : 解码数据库中的角色原始二进制数据，CRC32校验失败时返回错误
+func DecodeRole(data []byte) (*gameencoder.RoleEncoder, error) {
+	en := gameencoder.NewRoleEncoder()
+	en.SetLogger(discardLog)
+
+	if !en.Decode(data) {
+		return nil, fmt.Errorf("gamedb: failed to decode role data")
+	}
+	if en.CRC32Cal != en.CRC32Read {
+		return nil, fmt.Errorf("gamedb: role data CRC32 mismatch : %08x != %08x", en.CRC32Cal, en.CRC32Read)
+	}
+	return en, nil
+}
+
+// encodeRole : 编码角色数据，并将编码时清除的过期称号加入修改报告
+func encodeRole(en *gameencoder.RoleEncoder, report *[]gameencoder.RoleDiffEntry) ([]byte, error) {
+	data, err := en.Encode()
+	if err != nil {
+		return nil, err
+	}
+	*report = append(*report, en.PurgedTitleDiffs()...)
+	return data, nil
+}
+
+// EditRole : 在事务中解码角色数据，调用edit修改后重新编码写回，返回edit报告的修改字段和编码时清除的过期称号
+// edit只能修改角色数据，修改角色名和帐号需要同时修改角色表，应使用RenameRole和MoveRole
+func (d *RoleDB) EditRole(ctx context.Context, name string, edit func(en *gameencoder.RoleEncoder) ([]gameencoder.RoleDiffEntry, error)) ([]gameencoder.RoleDiffEntry, error) {
+	var report []gameencoder.RoleDiffEntry
+
+	err := d.UpdateRoleData(ctx, name, func(data []byte) ([]byte, error) {
+		en, err := DecodeRole(data)
+		if err != nil {
+			return nil, err
+		}
+		if report, err = edit(en); err != nil {
+			return nil, err
+		}
+		return encodeRole(en, &report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// MoveRole : 将角色移动到另一个帐号，在同一个事务中修改角色数据中的帐号名和角色表的帐号字段
+func (d *RoleDB) MoveRole(ctx context.Context, name, account string) ([]gameencoder.RoleDiffEntry, error) {
+	if err := gameencoder.ValidateAccount(account); err != nil {
+		return nil, err
+	}
+
+	var report []gameencoder.RoleDiffEntry
+
+	err := d.MoveRoleToAccount(ctx, name, account, func(data []byte) ([]byte, error) {
+		en, err := DecodeRole(data)
+		if err != nil {
+			return nil, err
+		}
+		if report, err = en.SetAccount(account); err != nil {
+			return nil, err
+		}
+		return encodeRole(en, &report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RenameRole : 重命名角色，在同一个事务中修改角色数据中的角色名、物品归属人和角色表的角色名字段
+func (d *RoleDB) RenameRole(ctx context.Context, name, newName string) ([]gameencoder.RoleDiffEntry, error) {
+	if err := gameencoder.ValidateRoleName(newName); err != nil {
+		return nil, err
+	}
+	if name == newName {
+		return nil, fmt.Errorf("gamedb: role %s already has this name", name)
+	}
+
+	var report []gameencoder.RoleDiffEntry
+
+	err := d.RenameRoleRecord(ctx, name, newName, func(data []byte) ([]byte, error) {
+		en, err := DecodeRole(data)
+		if err != nil {
+			return nil, err
+		}
+		if report, err = en.Rename(newName); err != nil {
+			return nil, err
+		}
+		return encodeRole(en, &report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,148 @@
+package gamedb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/heartchord/jxonline/gameencoder"
+)
+
+// roleDataForTest : 生成随机角色数据，失败时终止测试
+func roleDataForTest(t *testing.T, seed int64) []byte {
+	t.Helper()
+
+	opts := gameencoder.DefaultRoleGenOptions()
+	opts.Seed = seed
+	data, err := gameencoder.NewRoleGenerator(opts).RoleData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// decodeRecordForTest : 解码内存角色表中的角色数据
+func decodeRecordForTest(t *testing.T, store *fakeStore, name string) *gameencoder.RoleEncoder {
+	t.Helper()
+
+	row := store.find(name)
+	if row == nil {
+		t.Fatalf("role %s not found", name)
+	}
+	en, err := DecodeRole(row["RoleData"].([]byte))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return en
+}
+
+func TestMoveRole(t *testing.T) {
+	d, store := newRoleDBForTest(t)
+	store.add("Role1", "Acc1", roleDataForTest(t, 1))
+	store.add("Role2", "Acc2", roleDataForTest(t, 2))
+	ctx := context.Background()
+
+	report, err := d.MoveRole(ctx, "Role1", "Acc2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) == 0 || report[0].Field != "Account" || report[0].New != "Acc2" {
+		t.Fatalf("bad report : %v", report)
+	}
+
+	// 角色表的帐号字段和角色数据中的帐号名在同一个事务中修改
+	if account := string(store.find("Role1")["Account"].([]byte)); account != "Acc2" {
+		t.Fatalf("account column = %q", account)
+	}
+	if account := decodeRecordForTest(t, store, "Role1").RoleBaseData.Account.String(); account != "Acc2" {
+		t.Fatalf("account in role data = %q", account)
+	}
+	if count, err := d.CountRoles(ctx, "Acc2"); err != nil || count != 2 {
+		t.Fatalf("Acc2 has %d roles, %v", count, err)
+	}
+
+	if _, err := d.MoveRole(ctx, "Role1", "Acc2"); err == nil {
+		t.Fatal("move to the same account accepted")
+	}
+	if _, err := d.MoveRole(ctx, "Role1", ""); err == nil {
+		t.Fatal("empty account accepted")
+	}
+}
+
+func TestMoveRoleAccountFull(t *testing.T) {
+	d, store := newRoleDBForTest(t)
+	data := roleDataForTest(t, 1)
+	store.add("Role0", "Acc1", data)
+	for i := 1; i <= d.MaxRolesPerAccount; i++ {
+		store.add(fmt.Sprintf("Role%d", i), "Acc2", roleDataForTest(t, int64(i+1)))
+	}
+
+	_, err := d.MoveRole(context.Background(), "Role0", "Acc2")
+	if !errors.Is(err, ErrAccountFull) {
+		t.Fatalf("error = %v, want ErrAccountFull", err)
+	}
+	if store.rollbacks != 1 || store.commits != 0 {
+		t.Fatalf("commits = %d, rollbacks = %d", store.commits, store.rollbacks)
+	}
+
+	row := store.find("Role0")
+	if string(row["Account"].([]byte)) != "Acc1" || string(row["RoleData"].([]byte)) != string(data) {
+		t.Fatal("role modified by failed move")
+	}
+}
+
+func TestRenameRole(t *testing.T) {
+	d, store := newRoleDBForTest(t)
+	store.add("Role1", "Acc1", roleDataForTest(t, 1))
+	store.add("Role2", "Acc1", roleDataForTest(t, 2))
+	ctx := context.Background()
+
+	if _, err := d.RenameRole(ctx, "Role1", "Role2"); !errors.Is(err, ErrRoleExists) {
+		t.Fatalf("error = %v, want ErrRoleExists", err)
+	}
+	if _, err := d.RenameRole(ctx, "Missing", "Role3"); err != ErrRoleNotFound {
+		t.Fatalf("error = %v, want ErrRoleNotFound", err)
+	}
+
+	if _, err := d.RenameRole(ctx, "Role1", "Role3"); err != nil {
+		t.Fatal(err)
+	}
+	if store.find("Role1") != nil {
+		t.Fatal("old name left in role table")
+	}
+	if name := decodeRecordForTest(t, store, "Role3").RoleBaseData.RoleName.String(); name != "Role3" {
+		t.Fatalf("role name in role data = %q", name)
+	}
+}
+
+func TestEditRole(t *testing.T) {
+	d, store := newRoleDBForTest(t)
+	data := roleDataForTest(t, 1)
+	store.add("Role1", "Acc1", data)
+	ctx := context.Background()
+
+	_, err := d.EditRole(ctx, "Role1", func(en *gameencoder.RoleEncoder) ([]gameencoder.RoleDiffEntry, error) {
+		en.RoleBaseData.RoleLevel = 1
+		return nil, fmt.Errorf("broken")
+	})
+	if err == nil {
+		t.Fatal("edit error not returned")
+	}
+	if string(store.find("Role1")["RoleData"].([]byte)) != string(data) {
+		t.Fatal("role modified by failed edit")
+	}
+
+	report, err := d.EditRole(ctx, "Role1", func(en *gameencoder.RoleEncoder) ([]gameencoder.RoleDiffEntry, error) {
+		return en.ResetAllPasswords(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) == 0 {
+		t.Fatal("no change reported")
+	}
+	if en := decodeRecordForTest(t, store, "Role1"); en.RoleBaseData.BoxPassword != 0 || en.RoleExtData.LingLongLock.Locked != 0 {
+		t.Fatal("passwords not reset in role table")
+	}
+}
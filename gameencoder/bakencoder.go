@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
 )
 
 // LogWriter :
@@ -16,6 +18,11 @@ type RoleBakHeader struct {
 	RoleDataLen uint32 // 角色原始二进制数据长度
 }
 
+// RoleName : 获取Bak数据头中的角色名(UTF-8)
+func (h RoleBakHeader) RoleName() string {
+	return gmstruct.DecodeGBK(h.RoleNameGBK)
+}
+
 // RoleBakBody : a data struct of role bak data body
 type RoleBakBody struct {
 	RoleData []byte // Bak角色原始二进制数据
@@ -65,9 +72,38 @@ func (en *RoleBakEncoder) Decode(data []byte) bool {
 	return true
 }
 
+// Encode : function to encode role data into original role bak data
+// Bak数据头中的角色名使用角色基础数据中的角色名
+func (en *RoleBakEncoder) Encode() ([]byte, error) {
+	data, err := en.RoleEncoder.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	name := en.RoleBaseData.RoleName.Bytes()
+	if len(name) == 0 {
+		name = en.BakData.RoleNameGBK
+	}
+
+	en.BakData.RoleNameLen = uint32(len(name)) + 1 // 包含'\0'结束符
+	en.BakData.RoleNameGBK = append([]byte(nil), name...)
+	en.BakData.RoleDataLen = uint32(len(data))
+	en.BakData.RoleData = data
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, en.BakData.RoleNameLen)
+	buf.Write(en.BakData.RoleNameGBK)
+	buf.WriteByte(0)
+	binary.Write(buf, binary.LittleEndian, en.BakData.RoleDataLen)
+	buf.Write(data)
+
+	return buf.Bytes(), nil
+}
+
 func (en *RoleBakEncoder) decodeBakHeader(data []byte) bool {
-	dataLen := uint32(len(data))
-	current := uint32(0)
+	// 长度字段来自外部数据，使用uint64计算避免uint32溢出
+	dataLen := uint64(len(data))
+	current := uint64(0)
 
 	if dataLen <= 4 { // 数据长度 <= 角色名数据头长度
 		return false
@@ -81,16 +117,16 @@ func (en *RoleBakEncoder) decodeBakHeader(data []byte) bool {
 	current += 4
 
 	// 获取角色名
-	n := 4 + en.BakData.RoleNameLen - 1              // 要去掉'\0'字符
-	if en.BakData.RoleNameLen <= 0 || dataLen <= n { // 角色名长度 <= 0 或 数据长度 <= 角色名数据头长度 + 角色名长度
+	nameLen := uint64(en.BakData.RoleNameLen)
+	if nameLen == 0 || dataLen <= 4+nameLen-1 { // 角色名长度 <= 0 或 数据长度 <= 角色名数据头长度 + 角色名长度
 		return false
 	}
 
-	en.BakData.RoleNameGBK = data[current:n] // [4, 4 + namelen]存储角色名
-	current += en.BakData.RoleNameLen
+	en.BakData.RoleNameGBK = data[current : 4+nameLen-1] // [4, 4 + namelen - 1)存储角色名，要去掉'\0'字符
+	current += nameLen
 
 	// 获取角色原始数据长度
-	n = 4 + en.BakData.RoleNameLen + 4
+	n := 4 + nameLen + 4
 	if dataLen <= n { // 数据长度 <= 角色名数据头长度 + 角色名长度 + 角色数据长度
 		return false
 	}
@@ -100,11 +136,11 @@ func (en *RoleBakEncoder) decodeBakHeader(data []byte) bool {
 	current += 4
 
 	// 获取角色原始数据
-	n = 4 + en.BakData.RoleNameLen + 4 + en.BakData.RoleDataLen
+	n += uint64(en.BakData.RoleDataLen)
 	if dataLen < n {
 		return false
 	}
-	en.BakData.RoleData = data[current:n]
+	en.BakData.RoleData = data[current:n:n]
 
 	return true
 }
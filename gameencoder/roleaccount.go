@@ -0,0 +1,17 @@
+package gameencoder
+
+// SetAccount : function to move role to another account, returns modified fields
+// 只修改角色数据中的帐号名，数据库中角色表的帐号字段需要同时修改
+func (en *RoleEncoder) SetAccount(account string) ([]RoleDiffEntry, error) {
+	if err := ValidateAccount(account); err != nil {
+		return nil, err
+	}
+
+	old := en.RoleBaseData.Account.String()
+	en.RoleBaseData.Account.Set(account)
+	if old == en.RoleBaseData.Account.String() {
+		return nil, nil
+	}
+
+	return []RoleDiffEntry{{Section: "Base", Field: "Account", Old: old, New: en.RoleBaseData.Account.String()}}, nil
+}
@@ -0,0 +1,110 @@
+package gameencoder
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// Anonymizer : 角色数据脱敏器，用于将真实角色数据作为测试数据分享
+// 化名和GUID由盐值和原始值确定，同一盐值下多个角色之间的对应关系保持一致
+type Anonymizer struct {
+	salt []byte
+}
+
+// MinAnonymizerSaltLen : 盐值的最小字节数，盐值过短时可以通过枚举常见名称还原化名
+const MinAnonymizerSaltLen = 16
+
+// NewAnonymizer : 根据盐值创建一个Anonymizer对象，盐值应为不公开的随机字符串
+func NewAnonymizer(salt string) (*Anonymizer, error) {
+	if len(salt) < MinAnonymizerSaltLen {
+		return nil, fmt.Errorf("gameencoder: anonymizer salt is %d bytes, min %d", len(salt), MinAnonymizerSaltLen)
+	}
+	return &Anonymizer{salt: []byte(salt)}, nil
+}
+
+// sum : 计算带盐值的摘要
+func (a *Anonymizer) sum(kind string, data []byte) []byte {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Pseudonym : 获取名称的化名，格式为"前缀+8位十六进制数"，空名称保持为空
+func (a *Anonymizer) Pseudonym(prefix string, name gmstruct.GBKName) gmstruct.GBKName {
+	if name.IsEmpty() {
+		return name
+	}
+
+	var ret gmstruct.GBKName
+	ret.Set(prefix + hex.EncodeToString(a.sum(prefix, name.Bytes())[:4]))
+	return ret
+}
+
+// GUID : 获取GUID的化名，0保持为0
+func (a *Anonymizer) GUID(guid int64) int64 {
+	if guid == 0 {
+		return 0
+	}
+
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(guid))
+	v := int64(binary.LittleEndian.Uint64(a.sum("GUID", b[:])) & math.MaxInt64)
+	if v == 0 {
+		v = 1
+	}
+	return v
+}
+
+// Anonymize : function to replace personal data of decoded role with pseudonyms
+// 角色名、帐号名和物品归属人替换为化名，清除密码和玲珑锁，GUID统一重新映射
+func (a *Anonymizer) Anonymize(en *RoleEncoder) {
+	base := &en.RoleBaseData
+	base.RoleName = a.Pseudonym("R", base.RoleName)
+	base.Alias = a.Pseudonym("R", base.Alias)
+	base.Account = a.Pseudonym("A", base.Account)
+
+	if !base.PrimaryKey.IsEmpty() {
+		base.PrimaryKey.Set(hex.EncodeToString(a.sum("PrimaryKey", base.PrimaryKey.Bytes())[:16]))
+	}
+
+	ext := &en.RoleExtData
+	ext.Base.RoleNameGUID = a.GUID(ext.Base.RoleNameGUID)
+
+	// 清除密码、密码状态和玲珑锁，避免导出的角色处于锁定状态而密码为0
+	clearRolePasswords(en)
+
+	for i := range en.ItemData {
+		item := &en.ItemData[i]
+		if item.HasLockSoul {
+			item.LockSoul.Owner = a.Pseudonym("R", item.LockSoul.Owner)
+			item.LockSoul.ItemGUID = a.GUID(item.LockSoul.ItemGUID)
+			item.LockSoul.OwnerGUID = a.GUID(item.LockSoul.OwnerGUID)
+		}
+		if item.HasBill {
+			item.Bill.ItemGUID = a.GUID(item.Bill.ItemGUID)
+		}
+		if item.HasExtend {
+			item.Extend.OwnerName = a.Pseudonym("R", item.Extend.OwnerName)
+		}
+	}
+}
+
+// AnonymizeRole : function to anonymize role and encode it as DB role data
+func (a *Anonymizer) AnonymizeRole(en *RoleEncoder) ([]byte, error) {
+	a.Anonymize(en)
+	return en.Encode()
+}
+
+// AnonymizeBak : function to anonymize role and encode it as bak data
+func (a *Anonymizer) AnonymizeBak(en *RoleBakEncoder) ([]byte, error) {
+	a.Anonymize(&en.RoleEncoder)
+	return en.Encode()
+}
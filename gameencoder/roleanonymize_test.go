@@ -0,0 +1,41 @@
+package gameencoder
+
+import (
+	"testing"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+func TestAnonymizeClearsPasswords(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	if !en.RoleExtData.HasBase || !en.RoleExtData.HasLingLongLock {
+		t.Fatal("generated role has no ext base or LingLongLock data")
+	}
+	en.RoleBaseData.BoxPassword = 1234
+	en.RoleBaseData.BoxPasswordParam = 1
+	en.RoleExtData.Base.HavePassword = 1
+	en.RoleExtData.LingLongLock.Locked = 1
+	en.RoleExtData.LingLongLock.Timeout = 100
+
+	a, err := NewAnonymizer("0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := en.RoleBaseData.RoleName
+	data, err := a.AnonymizeRole(en)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := decodeForTest(t, data)
+	base, ext := &out.RoleBaseData, &out.RoleExtData
+	if base.RoleName == name {
+		t.Fatal("role name not replaced")
+	}
+	if base.BoxPassword != 0 || base.BoxPasswordParam != 0 || ext.Base.Password != 0 || ext.Base.HavePassword != 0 {
+		t.Fatal("password state left")
+	}
+	if ext.LingLongLock != (gmstruct.RoleExtDataOfLingLongLock{}) {
+		t.Fatalf("LingLongLock left : %+v", ext.LingLongLock)
+	}
+}
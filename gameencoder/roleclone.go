@@ -0,0 +1,125 @@
+package gameencoder
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CloneOptions : 从模板角色创建新角色的选项
+type CloneOptions struct {
+	RoleName string       // 新角色名
+	Account  string       // 新角色所属帐号
+	NewGUID  func() int64 // GUID生成函数，为空时使用随机GUID
+	Now      time.Time    // 角色创建时间，为零值时使用当前时间
+}
+
+// RandomGUID : 生成一个非0的随机正整数GUID
+func RandomGUID() int64 {
+	var v uint64
+	for v == 0 {
+		binary.Read(rand.Reader, binary.LittleEndian, &v)
+		v &= math.MaxInt64
+	}
+	return int64(v)
+}
+
+// rolePrimaryKey : 根据帐号、角色名和创建时间生成角色唯一标识（MD5的十六进制字符串）
+func rolePrimaryKey(account, name string, now time.Time) string {
+	sum := md5.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d", account, name, now.UnixNano())))
+	return hex.EncodeToString(sum[:])
+}
+
+// CloneRole : function to create a new role from a template role with fresh identity
+// 模板角色不会被修改，返回新角色及其Bak数据
+func CloneRole(template *RoleEncoder, opts *CloneOptions) (*RoleBakEncoder, []byte, error) {
+	if opts == nil {
+		return nil, nil, fmt.Errorf("gameencoder: no clone options")
+	}
+
+	newGUID := opts.NewGUID
+	if newGUID == nil {
+		newGUID = RandomGUID
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	bak := NewRoleBakEncoder()
+	bak.RoleEncoder = *template.Clone()
+	bak.RoleEncoder.Init()
+
+	role := &bak.RoleEncoder
+	base := &role.RoleBaseData
+
+	// 角色标识
+	if err := ValidateRoleName(opts.RoleName); err != nil {
+		return nil, nil, err
+	}
+	base.RoleName.Set(opts.RoleName)
+	if err := ValidateAccount(opts.Account); err != nil {
+		return nil, nil, err
+	}
+	base.Account.Set(opts.Account)
+
+	base.PrimaryKey.Set(rolePrimaryKey(opts.Account, opts.RoleName, now))
+
+	base.RoleCreateTime = uint32(now.Unix())
+	base.LastLogoutTime = 0
+	base.TongID = 0
+
+	// 没有扩展基础数据的角色没有角色GUID，物品锁魂不绑定角色GUID
+	roleGUID := int64(0)
+	if role.RoleExtData.HasBase {
+		roleGUID = newGUID()
+		role.RoleExtData.Base.RoleNameGUID = roleGUID
+	}
+
+	// 密码和防外挂数据
+	clearRolePasswords(role)
+	base.CatchTimeForAntiBot = 0
+	base.RefuseLoginCount = 0
+	base.HaveRefuseLogin = 0
+	base.IsExchanged = 0
+	base.IsExchangeServer = 0
+
+	// 物品GUID，同一个原GUID映射为同一个新GUID
+	guids := make(map[int64]int64)
+	renew := func(guid int64) int64 {
+		if guid == 0 {
+			return 0
+		}
+		if _, ok := guids[guid]; !ok {
+			guids[guid] = newGUID()
+		}
+		return guids[guid]
+	}
+
+	for i := range role.ItemData {
+		item := &role.ItemData[i]
+		if item.HasLockSoul {
+			item.LockSoul.ItemGUID = renew(item.LockSoul.ItemGUID)
+			if item.LockSoul.OwnerGUID != 0 {
+				item.LockSoul.OwnerGUID = roleGUID
+				item.LockSoul.Owner = base.RoleName
+			}
+		}
+		if item.HasBill {
+			item.Bill.ItemGUID = renew(item.Bill.ItemGUID)
+		}
+		if item.HasExtend && !item.Extend.OwnerName.IsEmpty() {
+			item.Extend.OwnerName = base.RoleName
+		}
+	}
+
+	data, err := bak.Encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	return bak, data, nil
+}
@@ -0,0 +1,82 @@
+package gameencoder
+
+import (
+	"testing"
+	"time"
+)
+
+// cloneOptionsForTest : 使用递增GUID的克隆选项
+func cloneOptionsForTest() *CloneOptions {
+	next := int64(5000)
+	return &CloneOptions{
+		RoleName: "NewRole",
+		Account:  "NewAccount",
+		NewGUID:  func() int64 { next++; return next },
+		Now:      time.Unix(1500000000, 0),
+	}
+}
+
+func TestCloneRole(t *testing.T) {
+	template := decodeForTest(t, generateForTest(t, 1))
+	old := template.Clone()
+
+	role, data, err := CloneRole(template, cloneOptionsForTest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(DiffRole(old, template)) != 0 {
+		t.Fatal("template modified")
+	}
+
+	bak := NewRoleBakEncoder()
+	bak.SetLogger(quietLog)
+	if !bak.Decode(data) || bak.CRC32Cal != bak.CRC32Read {
+		t.Fatal("cloned bak cannot be decoded")
+	}
+
+	base := &role.RoleBaseData
+	if base.RoleName.String() != "NewRole" || base.Account.String() != "NewAccount" || base.PrimaryKey == old.RoleBaseData.PrimaryKey {
+		t.Fatalf("identity not replaced : %s / %s / %s", base.RoleName, base.Account, base.PrimaryKey)
+	}
+	roleGUID := role.RoleExtData.Base.RoleNameGUID
+	if roleGUID != 5001 {
+		t.Fatalf("role GUID = %d, want 5001", roleGUID)
+	}
+
+	// 同一个原GUID映射为同一个新GUID，锁魂物品归属新角色
+	for i := range role.ItemData {
+		item, src := &role.ItemData[i], &old.ItemData[i]
+		if item.HasLockSoul && item.HasBill && (src.LockSoul.ItemGUID == src.Bill.ItemGUID) != (item.LockSoul.ItemGUID == item.Bill.ItemGUID) {
+			t.Fatalf("item %d GUIDs mapped inconsistently", i)
+		}
+		if item.HasLockSoul && src.LockSoul.OwnerGUID != 0 {
+			if item.LockSoul.OwnerGUID != roleGUID || item.LockSoul.Owner != base.RoleName {
+				t.Fatalf("item %d not bound to new role", i)
+			}
+		}
+	}
+}
+
+func TestCloneRoleWithoutExtBase(t *testing.T) {
+	opts := DefaultRoleGenOptions()
+	opts.ExtData = false
+	template, err := NewRoleGenerator(opts).Role()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	role, _, err := CloneRole(template, cloneOptionsForTest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 没有角色GUID时锁魂物品不能绑定到不存在的GUID
+	for i := range role.ItemData {
+		if item := &role.ItemData[i]; item.HasLockSoul && item.LockSoul.OwnerGUID != 0 {
+			t.Fatalf("item %d bound to GUID %d", i, item.LockSoul.OwnerGUID)
+		}
+	}
+
+	if _, _, err := CloneRole(template, nil); err == nil {
+		t.Fatal("nil options accepted")
+	}
+}
@@ -0,0 +1,343 @@
+package gameencoder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// RoleDiffEntry : 角色数据差异项
+type RoleDiffEntry struct {
+	Section string // 数据区块名称
+	Field   string // 数据名称
+	Old     string // 原始数据内容，为空表示新增
+	New     string // 新数据内容，为空表示删除
+}
+
+// String : 格式化差异项
+func (d RoleDiffEntry) String() string {
+	return fmt.Sprintf("[%s] %s : %s -> %s", d.Section, d.Field, d.Old, d.New)
+}
+
+// DiffRole : function to compare two decoded roles and list all differences
+func DiffRole(a, b *RoleEncoder) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+
+	diffs = append(diffs, diffRoleBaseData(a, b)...)
+	diffs = append(diffs, diffSkillData("FSkill", a.FSkillData, b.FSkillData, a.skillCatalog)...)
+	diffs = append(diffs, diffSkillData("LSkill", a.LSkillData, b.LSkillData, a.skillCatalog)...)
+	diffs = append(diffs, diffTaskData(a.TaskData, b.TaskData, a.taskCatalog)...)
+	diffs = append(diffs, diffItemData(a.ItemData, b.ItemData, a.itemCatalog)...)
+	diffs = append(diffs, diffStructList("SkillState", a.SkillState, b.SkillState)...)
+	diffs = append(diffs, diffStructList("SkillCD", a.SkillCD, b.SkillCD)...)
+	diffs = append(diffs, diffStructList("FeatureInfo", a.FeatureInfo, b.FeatureInfo)...)
+	diffs = append(diffs, diffStructList("PlayerEvent", a.PlayerEvent, b.PlayerEvent)...)
+	diffs = append(diffs, diffStructList("PlayerTitle", a.PlayerTitle, b.PlayerTitle)...)
+	diffs = append(diffs, diffStructList("MaxSkillLevel", a.MaxSkillLevel, b.MaxSkillLevel)...)
+	diffs = append(diffs, diffRoleExtData(a, b)...)
+
+	return diffs
+}
+
+// PrintRoleDiff : function to print differences of two decoded roles
+func PrintRoleDiff(diffs []RoleDiffEntry) {
+	fmt.Println("=================================[ROLE DIFF]==================================")
+	fmt.Printf("Total = %d\n", len(diffs))
+
+	for _, d := range diffs {
+		fmt.Println(d.String())
+	}
+}
+
+func diffRoleBaseData(a, b *RoleEncoder) []RoleDiffEntry {
+	// 经验拆分存储，按组合后的值比较
+	skip := map[string]bool{"ExpHigh": true, "ExpLow": true}
+	diffs := diffStructFields("Base", "", reflect.ValueOf(a.RoleBaseData), reflect.ValueOf(b.RoleBaseData), skip)
+
+	if a.RoleBaseData.Exp() != b.RoleBaseData.Exp() {
+		diffs = append(diffs, RoleDiffEntry{
+			Section: "Base",
+			Field:   "Exp",
+			Old:     fmt.Sprintf("%d", a.RoleBaseData.Exp()),
+			New:     fmt.Sprintf("%d", b.RoleBaseData.Exp()),
+		})
+	}
+
+	return diffs
+}
+
+func diffRoleExtData(a, b *RoleEncoder) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+
+	ea, eb := a.RoleExtData, b.RoleExtData
+
+	diffs = append(diffs, diffExtBlock("Base", ea.HasBase, eb.HasBase, ea.Base, eb.Base, nil)...)
+	diffs = append(diffs, diffExtBlock("LingLongLock", ea.HasLingLongLock, eb.HasLingLongLock, ea.LingLongLock, eb.LingLongLock, nil)...)
+	diffs = append(diffs, diffExtBlock("HangerOn", ea.HasHangerOn, eb.HasHangerOn, ea.HangerOn, eb.HangerOn, nil)...)
+
+	// 转灵经验拆分存储，按组合后的值比较
+	skip := map[string]bool{"TransNimbusExpHigh": true, "TransNimbusExpLow": true}
+	diffs = append(diffs, diffExtBlock("TransNimbus", ea.HasTransNimbus, eb.HasTransNimbus, ea.TransNimbus, eb.TransNimbus, skip)...)
+	if ea.HasTransNimbus && eb.HasTransNimbus && ea.TransNimbus.Exp() != eb.TransNimbus.Exp() {
+		diffs = append(diffs, RoleDiffEntry{
+			Section: "Ext.TransNimbus",
+			Field:   "TransNimbusExp",
+			Old:     fmt.Sprintf("%d", ea.TransNimbus.Exp()),
+			New:     fmt.Sprintf("%d", eb.TransNimbus.Exp()),
+		})
+	}
+
+	diffs = append(diffs, diffExtBlock("Break", ea.HasBreak, eb.HasBreak, ea.Break, eb.Break, nil)...)
+	diffs = append(diffs, diffExtBlock("EquipCompose", ea.HasEquipCompose, eb.HasEquipCompose, ea.EquipCompose, eb.EquipCompose, nil)...)
+
+	return diffs
+}
+
+func diffExtBlock(name string, hasA, hasB bool, a, b interface{}, skip map[string]bool) []RoleDiffEntry {
+	section := "Ext." + name
+
+	switch {
+	case !hasA && !hasB:
+		return nil
+	case !hasA:
+		return []RoleDiffEntry{{Section: section, Field: "Has" + name, Old: "false", New: "true"}}
+	case !hasB:
+		return []RoleDiffEntry{{Section: section, Field: "Has" + name, Old: "true", New: "false"}}
+	}
+
+	return diffStructFields(section, "", reflect.ValueOf(a), reflect.ValueOf(b), skip)
+}
+
+// diffStructFields : 逐个比较结构体成员，匿名结构体成员展开比较
+func diffStructFields(section, prefix string, a, b reflect.Value, skip map[string]bool) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if skip[field.Name] {
+			continue
+		}
+
+		fa, fb := a.Field(i), b.Field(i)
+		if field.Anonymous && fa.Kind() == reflect.Struct {
+			diffs = append(diffs, diffStructFields(section, prefix, fa, fb, skip)...)
+			continue
+		}
+
+		name := prefix + field.Name
+		if fa.Kind() == reflect.Struct {
+			diffs = append(diffs, diffStructFields(section, name+".", fa, fb, skip)...)
+			continue
+		}
+
+		if reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+
+		diffs = append(diffs, RoleDiffEntry{
+			Section: section,
+			Field:   name,
+			Old:     fmt.Sprintf("%v", fa.Interface()),
+			New:     fmt.Sprintf("%v", fb.Interface()),
+		})
+	}
+
+	return diffs
+}
+
+func diffSkillData(section string, a, b []gmstruct.SkillData, catalog gamecatalog.SkillCatalog) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+
+	ma := make(map[int16]gmstruct.SkillData)
+	mb := make(map[int16]gmstruct.SkillData)
+	var ids []int
+
+	for _, s := range a {
+		ma[s.SkillID] = s
+		ids = append(ids, int(s.SkillID))
+	}
+	for _, s := range b {
+		if _, ok := ma[s.SkillID]; !ok {
+			ids = append(ids, int(s.SkillID))
+		}
+		mb[s.SkillID] = s
+	}
+	sort.Ints(ids)
+
+	format := func(s gmstruct.SkillData) string {
+		return fmt.Sprintf("{ Lv = %d, Exp = %d }", s.SkillLv, s.SkillExp)
+	}
+
+	for _, id := range ids {
+		sa, okA := ma[int16(id)]
+		sb, okB := mb[int16(id)]
+
+		d := RoleDiffEntry{Section: section, Field: fmt.Sprintf("Skill[%d]", id)}
+		if name := gamecatalog.SkillName(catalog, id); name != "" {
+			d.Field += " " + name
+		}
+		switch {
+		case okA && okB && sa == sb:
+			continue
+		case okA && okB:
+			d.Old, d.New = format(sa), format(sb)
+		case okA:
+			d.Old = format(sa)
+		default:
+			d.New = format(sb)
+		}
+		diffs = append(diffs, d)
+	}
+
+	return diffs
+}
+
+func diffTaskData(a, b []gmstruct.TaskData, catalog gamecatalog.TaskCatalog) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+
+	ma := make(map[int32]int32)
+	mb := make(map[int32]int32)
+	var ids []int
+
+	for _, t := range a {
+		ma[t.TaskID] = t.TaskValue
+		ids = append(ids, int(t.TaskID))
+	}
+	for _, t := range b {
+		if _, ok := ma[t.TaskID]; !ok {
+			ids = append(ids, int(t.TaskID))
+		}
+		mb[t.TaskID] = t.TaskValue
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		va, okA := ma[int32(id)]
+		vb, okB := mb[int32(id)]
+
+		d := RoleDiffEntry{Section: "Task", Field: fmt.Sprintf("Task[%d]", id)}
+		if name := gamecatalog.TaskName(catalog, id); name != "" {
+			d.Field += " " + name
+		}
+
+		// 设置了任务变量配置时附带变量值的含义
+		format := func(v int32) string {
+			if info, ok := catalogTask(catalog, id); ok {
+				if meaning := info.Decode(v); meaning != "" {
+					return fmt.Sprintf("%d (%s)", v, meaning)
+				}
+			}
+			return fmt.Sprintf("%d", v)
+		}
+
+		switch {
+		case okA && okB && va == vb:
+			continue
+		case okA && okB:
+			d.Old, d.New = format(va), format(vb)
+		case okA:
+			d.Old = format(va)
+		default:
+			d.New = format(vb)
+		}
+		diffs = append(diffs, d)
+	}
+
+	return diffs
+}
+
+// catalogTask : 查询任务变量配置，未设置任务变量配置时返回false
+func catalogTask(catalog gamecatalog.TaskCatalog, id int) (*gamecatalog.TaskInfo, bool) {
+	if catalog == nil {
+		return nil, false
+	}
+	return catalog.LookupTask(id)
+}
+
+// itemDiffKey : 物品比较标识，有GUID时使用GUID，否则使用物品索引
+func itemDiffKey(item gmstruct.ItemData, index int) string {
+	if guid, ok := itemGUID(&item); ok {
+		return fmt.Sprintf("GUID=%d", guid)
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+func formatItemSummary(item gmstruct.ItemData, catalog gamecatalog.ItemCatalog) string {
+	summary := fmt.Sprintf("{ G = %d, D = %d, P = %d, Lv = %d, Place = %d, X = %d, Y = %d }",
+		item.Standard.ClassCode&0x0000FFFF, item.Standard.DetailType, item.Standard.ParticularType,
+		item.Standard.Level, item.Standard.Place, item.Standard.PosX, item.Standard.PosY)
+
+	if name := gamecatalog.ItemName(catalog, &item); name != "" {
+		summary = name + " " + summary
+	}
+	return summary
+}
+
+func diffItemData(a, b []gmstruct.ItemData, catalog gamecatalog.ItemCatalog) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+	var keys []string
+
+	ma := make(map[string]gmstruct.ItemData)
+	mb := make(map[string]gmstruct.ItemData)
+
+	for i, item := range a {
+		key := itemDiffKey(item, i)
+		ma[key] = item
+		keys = append(keys, key)
+	}
+	for i, item := range b {
+		key := itemDiffKey(item, i)
+		if _, ok := ma[key]; !ok {
+			keys = append(keys, key)
+		}
+		mb[key] = item
+	}
+
+	for _, key := range keys {
+		ia, okA := ma[key]
+		ib, okB := mb[key]
+
+		field := fmt.Sprintf("Item[%s]", key)
+		switch {
+		case okA && okB:
+			if !reflect.DeepEqual(ia, ib) {
+				diffs = append(diffs, diffStructFields("Item", field+".", reflect.ValueOf(ia), reflect.ValueOf(ib), nil)...)
+			}
+		case okA:
+			diffs = append(diffs, RoleDiffEntry{Section: "Item", Field: field, Old: formatItemSummary(ia, catalog)})
+		default:
+			diffs = append(diffs, RoleDiffEntry{Section: "Item", Field: field, New: formatItemSummary(ib, catalog)})
+		}
+	}
+
+	return diffs
+}
+
+// diffStructList : 按索引比较状态数据列表
+func diffStructList(section string, a, b interface{}) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	count := va.Len()
+	if vb.Len() > count {
+		count = vb.Len()
+	}
+
+	for i := 0; i < count; i++ {
+		field := fmt.Sprintf("%s[%d]", section, i)
+		switch {
+		case i >= va.Len():
+			diffs = append(diffs, RoleDiffEntry{Section: section, Field: field, New: fmt.Sprintf("%+v", vb.Index(i).Interface())})
+		case i >= vb.Len():
+			diffs = append(diffs, RoleDiffEntry{Section: section, Field: field, Old: fmt.Sprintf("%+v", va.Index(i).Interface())})
+		default:
+			diffs = append(diffs, diffStructFields(section, field+".", va.Index(i), vb.Index(i), nil)...)
+		}
+	}
+
+	return diffs
+}
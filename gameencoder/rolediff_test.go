@@ -0,0 +1,57 @@
+package gameencoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffRoleExp(t *testing.T) {
+	a := decodeForTest(t, generateForTest(t, 1))
+	b := a.Clone()
+
+	if diffs := DiffRole(a, b); len(diffs) != 0 {
+		t.Fatalf("clone differs : %v", diffs)
+	}
+
+	// 经验按组合后的值比较，不单独列出高位和低位
+	b.RoleBaseData.SetExp(a.RoleBaseData.Exp() + 1<<32 + 1)
+	b.RoleBaseData.RoleLevel++
+	diffs := DiffRole(a, b)
+	if len(diffs) != 2 {
+		t.Fatalf("diffs = %v, want RoleLevel and Exp", diffs)
+	}
+	for _, d := range diffs {
+		if d.Section != "Base" || (d.Field != "RoleLevel" && d.Field != "Exp") {
+			t.Fatalf("unexpected diff : %s", d)
+		}
+	}
+}
+
+func TestExportJSONExp(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	en.RoleBaseData.SetExp(0x123456789AB)
+
+	var buf bytes.Buffer
+	if err := en.ExportJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Exp           int64
+		LevelProgress *float64
+		ItemData      []json.RawMessage
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Exp != 0x123456789AB {
+		t.Fatalf("exported exp = %#x", out.Exp)
+	}
+	if out.LevelProgress != nil {
+		t.Fatal("level progress exported without exp table")
+	}
+	if len(out.ItemData) != len(en.ItemData) {
+		t.Fatalf("exported %d of %d items", len(out.ItemData), len(en.ItemData))
+	}
+}
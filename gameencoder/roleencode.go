@@ -0,0 +1,360 @@
+package gameencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// 物品数据头中的物品数据组成标志
+const (
+	itemDataFlagOfStandard = 1 << iota
+	itemDataFlagOfLockSoul
+	itemDataFlagOfBill
+	itemDataFlagOfExtend
+)
+
+// Encode : function to encode role data into original role binary data
+// 编码时清除已过期的称号，重新计算各数据区块的数量、偏移、数据长度和CRC32码，并同步更新RoleBaseData
+// 清除的称号可以通过PurgedTitles和PurgedTitleDiffs获取
+func (en *RoleEncoder) Encode() ([]byte, error) {
+	en.purgedTitles = en.PurgeExpiredTitles()
+
+	items, states, ext, err := en.layout()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	for _, v := range []interface{}{&en.RoleBaseData, en.FSkillData, en.LSkillData, en.TaskData} {
+		if err := binary.Write(buf, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+	buf.Write(items)
+	buf.Write(states)
+	buf.Write(en.extPadding)
+	buf.Write(ext)
+	buf.Write(en.tailPadding)
+
+	en.CRC32Cal = CRC32(0, buf.Bytes())
+	en.CRC32Read = en.CRC32Cal
+	binary.Write(buf, binary.LittleEndian, en.CRC32Cal)
+
+	return buf.Bytes(), nil
+}
+
+// UpdateLayout : function to recalculate counts and offsets of all data sections
+func (en *RoleEncoder) UpdateLayout() error {
+	_, _, _, err := en.layout()
+	return err
+}
+
+// layout : 编码物品、状态和扩展数据，并根据编码结果重新计算RoleBaseData中的数量和偏移
+func (en *RoleEncoder) layout() (items, states, ext []byte, err error) {
+	if len(en.FSkillData) > math.MaxInt16 || len(en.LSkillData) > math.MaxInt16 || len(en.ItemData) > math.MaxInt16 {
+		return nil, nil, nil, fmt.Errorf("gameencoder: too many skills or items to encode")
+	}
+
+	if items, err = en.encodeRoleItemData(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var stateCount int16
+	if states, stateCount, err = en.encodeRoleStateList(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if ext, err = en.encodeRoleExtData(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// 重新计算数量和偏移
+	base := &en.RoleBaseData
+	skillSize := uint32(binary.Size(gmstruct.SkillData{}))
+	taskSize := uint32(binary.Size(gmstruct.TaskData{}))
+
+	base.FightSkillCount = int16(len(en.FSkillData))
+	base.LiveSkillCount = int16(len(en.LSkillData))
+	base.ItemCount = int16(len(en.ItemData))
+	base.StateCount = stateCount
+
+	sizes := [6]int{
+		int(skillSize) * len(en.FSkillData),
+		int(skillSize) * len(en.LSkillData),
+		int(taskSize) * len(en.TaskData),
+		len(items),
+		len(states),
+		len(ext),
+	}
+
+	// 各区块实际位置，解码器按顺序读取各区块，扩展数据从ExtBuffOffset读取
+	fskill := uint32(binary.Size(*base))
+	state := fskill + uint32(sizes[0]+sizes[1]+sizes[2]+sizes[3])
+	extStart := state + uint32(len(states)+len(en.extPadding))
+
+	// 各区块大小都没有变化时保持解码时的原始偏移
+	if l := en.decodedLayout; l != nil && l.sizes == sizes {
+		base.FSkillOffset, base.LSkillOffset, base.TaskOffset = l.offsets[0], l.offsets[1], l.offsets[2]
+		base.ItemOffset, base.StateOffset, base.ExtBuffOffset = l.offsets[3], l.offsets[4], l.offsets[5]
+	} else {
+		base.FSkillOffset = fskill
+		base.LSkillOffset = base.FSkillOffset + uint32(sizes[0])
+		base.TaskOffset = base.LSkillOffset + uint32(sizes[1])
+		base.ItemOffset = base.TaskOffset + uint32(sizes[2])
+		base.StateOffset = state
+		base.ExtBuffOffset = 0
+		if len(ext) > 0 || len(en.extPadding) > 0 || (l != nil && l.offsets[5] != 0) {
+			base.ExtBuffOffset = extStart
+		}
+	}
+
+	// 数据总长度包含末尾的CRC32码
+	total := int64(extStart) + int64(len(ext)) + int64(len(en.tailPadding)) + 4
+	base.DataLen = uint32(total + en.dataLenPad)
+
+	return items, states, ext, nil
+}
+
+// itemDataFlags : 获取物品数据组成标志
+func itemDataFlags(item *gmstruct.ItemData) int32 {
+	flags := int32(0)
+	if item.HasStandard {
+		flags |= itemDataFlagOfStandard
+	}
+	if item.HasLockSoul {
+		flags |= itemDataFlagOfLockSoul
+	}
+	if item.HasBill {
+		flags |= itemDataFlagOfBill
+	}
+	if item.HasExtend {
+		flags |= itemDataFlagOfExtend
+	}
+	return flags
+}
+
+// encodeRoleItemData : 编码物品数据，相邻且组成相同的物品共用一个数据头
+func (en *RoleEncoder) encodeRoleItemData() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	headSize := binary.Size(gmstruct.DataHead{})
+
+	for i := 0; i < len(en.ItemData); {
+		flags := itemDataFlags(&en.ItemData[i])
+
+		j := i + 1
+		for j < len(en.ItemData) && itemDataFlags(&en.ItemData[j]) == flags {
+			j++
+		}
+
+		var parts bytes.Buffer
+		for k := i; k < j; k++ {
+			item := &en.ItemData[k]
+			if item.HasStandard {
+				binary.Write(&parts, binary.LittleEndian, &item.Standard)
+			}
+			if item.HasLockSoul {
+				binary.Write(&parts, binary.LittleEndian, &item.LockSoul)
+			}
+			if item.HasBill {
+				binary.Write(&parts, binary.LittleEndian, &item.Bill)
+			}
+			if item.HasExtend {
+				binary.Write(&parts, binary.LittleEndian, &item.Extend)
+			}
+		}
+
+		head := gmstruct.DataHead{
+			DataType:  flags,
+			DataCount: int16(j - i),
+			DataLen:   int32(headSize + parts.Len()),
+		}
+		if err := binary.Write(buf, binary.LittleEndian, &head); err != nil {
+			return nil, err
+		}
+		buf.Write(parts.Bytes())
+
+		i = j
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeStateData : 将状态数据编码为StateData
+func encodeStateData(t byte, v interface{}) ([]byte, error) {
+	var body bytes.Buffer
+	if err := binary.Write(&body, binary.LittleEndian, v); err != nil {
+		return nil, err
+	}
+
+	var state gmstruct.StateData
+	if body.Len() > len(state.Data) {
+		return nil, fmt.Errorf("gameencoder: state data of type %d is too large : %d", t, body.Len())
+	}
+	state.Type = t
+	copy(state.Data[:], body.Bytes())
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, &state)
+	return buf.Bytes(), nil
+}
+
+// encodeRoleStateList : 编码状态数据，按解码时的原始顺序排列，新增的状态数据按类型追加在末尾
+func (en *RoleEncoder) encodeRoleStateList() ([]byte, int16, error) {
+	queues := make(map[byte][][]byte)
+
+	push := func(t byte, v interface{}) error {
+		data, err := encodeStateData(t, v)
+		if err != nil {
+			return err
+		}
+		queues[t] = append(queues[t], data)
+		return nil
+	}
+
+	for i := range en.SkillState {
+		if err := push(gmstruct.SkillStateType, &en.SkillState[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range en.SkillCD {
+		if err := push(gmstruct.SkillCDType, &en.SkillCD[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range en.FeatureInfo {
+		if err := push(gmstruct.FeatureInfoType, &en.FeatureInfo[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range en.PlayerEvent {
+		if err := push(gmstruct.PlayerEventInfoType, &en.PlayerEvent[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range en.PlayerTitle {
+		if err := push(gmstruct.PlayerTitleType, &en.PlayerTitle[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range en.MaxSkillLevel {
+		if err := push(gmstruct.PlayerMaxSkillLevelType, &en.MaxSkillLevel[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	// 自定义数据原样写回
+	for _, data := range en.CustomStructData {
+		queues[gmstruct.CustomStructType] = append(queues[gmstruct.CustomStructType], data)
+	}
+
+	buf := new(bytes.Buffer)
+	count := 0
+
+	for _, t := range en.stateOrder {
+		if len(queues[t]) == 0 {
+			continue
+		}
+		buf.Write(queues[t][0])
+		queues[t] = queues[t][1:]
+		count++
+	}
+
+	var types []int
+	for t := range queues {
+		types = append(types, int(t))
+	}
+	sort.Ints(types)
+	for _, t := range types {
+		for _, data := range queues[byte(t)] {
+			buf.Write(data)
+			count++
+		}
+	}
+
+	if count > math.MaxInt16 {
+		return nil, 0, fmt.Errorf("gameencoder: too many states to encode : %d", count)
+	}
+	return buf.Bytes(), int16(count), nil
+}
+
+// roleExtDataBlock : 获取指定类型的角色扩展数据，角色没有该扩展数据时返回false
+func (en *RoleEncoder) roleExtDataBlock(t int32) (interface{}, bool) {
+	ext := &en.RoleExtData
+
+	switch t {
+	case roleExtDataOfBase:
+		return &ext.Base, ext.HasBase
+	case roleExtDataOfLingLongLock:
+		return &ext.LingLongLock, ext.HasLingLongLock
+	case roleExtDataTypeOfHangerOn:
+		return &ext.HangerOn, ext.HasHangerOn
+	case roleExtDataTypeOfTransNimbus:
+		return &ext.TransNimbus, ext.HasTransNimbus
+	case roleExtDataTypeOfBreak:
+		return &ext.Break, ext.HasBreak
+	case roleExtDataTypeOfEquipCompose:
+		return &ext.EquipCompose, ext.HasEquipCompose
+	}
+	return nil, false
+}
+
+// encodeRoleExtData : 编码角色扩展数据，按解码时的原始顺序排列，新增的扩展数据按类型追加在末尾
+func (en *RoleEncoder) encodeRoleExtData() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	headSize := binary.Size(gmstruct.DataHead{})
+	written := make(map[int32]bool)
+
+	write := func(head gmstruct.DataHead, block interface{}) error {
+		if err := binary.Write(buf, binary.LittleEndian, &head); err != nil {
+			return err
+		}
+		if block == nil {
+			return nil
+		}
+		return binary.Write(buf, binary.LittleEndian, block)
+	}
+
+	for _, head := range en.extDataHeads {
+		t := head.DataType >> 16
+
+		// 物品扩展数据只有数据头
+		if t == roleExtDataOfItem {
+			if err := write(head, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		block, ok := en.roleExtDataBlock(t)
+		if !ok || written[t] {
+			continue
+		}
+		if err := write(head, block); err != nil {
+			return nil, err
+		}
+		written[t] = true
+	}
+
+	for t := int32(roleExtDataOfBase); t < roleExtDataTypeCount; t++ {
+		block, ok := en.roleExtDataBlock(t)
+		if !ok || written[t] {
+			continue
+		}
+
+		head := gmstruct.DataHead{
+			DataType:  t << 16,
+			DataCount: 1,
+			DataLen:   int32(headSize + binary.Size(block)),
+		}
+		if err := write(head, block); err != nil {
+			return nil, err
+		}
+		written[t] = true
+	}
+
+	return buf.Bytes(), nil
+}
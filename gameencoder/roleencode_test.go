@@ -0,0 +1,254 @@
+package gameencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// quietLog : 测试中不输出解码日志
+func quietLog(format string, a ...interface{}) (int, error) {
+	return 0, nil
+}
+
+// decodeForTest : 解码角色数据，失败时终止测试
+func decodeForTest(tb testing.TB, data []byte) *RoleEncoder {
+	tb.Helper()
+
+	en := NewRoleEncoder()
+	en.SetLogger(quietLog)
+	if !en.Decode(data) {
+		tb.Fatalf("decode failed")
+	}
+	if en.CRC32Cal != en.CRC32Read {
+		tb.Fatalf("CRC32 mismatch : cal = %08x, read = %08x", en.CRC32Cal, en.CRC32Read)
+	}
+	return en
+}
+
+// generateForTest : 生成随机角色数据，失败时终止测试
+func generateForTest(tb testing.TB, seed int64) []byte {
+	tb.Helper()
+
+	opts := DefaultRoleGenOptions()
+	opts.Seed = seed
+	data, err := NewRoleGenerator(opts).RoleData()
+	if err != nil {
+		tb.Fatalf("generate seed %d : %v", seed, err)
+	}
+	return data
+}
+
+// patchBase : 修改角色数据中的RoleBaseData并重新计算CRC32码
+func patchBase(tb testing.TB, data []byte, patch func(base *gmstruct.RoleBaseData)) []byte {
+	tb.Helper()
+
+	var base gmstruct.RoleBaseData
+	size := binary.Size(base)
+	if err := binary.Read(bytes.NewReader(data[:size]), binary.LittleEndian, &base); err != nil {
+		tb.Fatal(err)
+	}
+	patch(&base)
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, &base)
+	out := append(buf.Bytes(), data[size:len(data)-4]...)
+	return binary.LittleEndian.AppendUint32(out, CRC32(0, out))
+}
+
+// assertReencode : 解码后不做修改重新编码，结果必须与输入完全一致
+func assertReencode(t *testing.T, data []byte) {
+	t.Helper()
+
+	out, err := decodeForTest(t, data).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Fatalf("re-encoded data differs : len %d -> %d", len(data), len(out))
+	}
+}
+
+func TestEncodeKeepsOriginalOffsets(t *testing.T) {
+	// 偏移整体带有偏差时，解码器按偏移差计算数量，编码应保持原始偏移
+	data := patchBase(t, generateForTest(t, 1), func(base *gmstruct.RoleBaseData) {
+		base.FSkillOffset += 100
+		base.LSkillOffset += 100
+		base.TaskOffset += 100
+		base.ItemOffset += 100
+		base.StateOffset += 100
+	})
+	assertReencode(t, data)
+}
+
+func TestEncodeKeepsExtPadding(t *testing.T) {
+	src := generateForTest(t, 2)
+	en := decodeForTest(t, src)
+	extStart := int(en.RoleBaseData.ExtBuffOffset)
+	if extStart == 0 {
+		t.Fatal("generated role has no ext data")
+	}
+
+	// 在状态数据和扩展数据之间插入填充字节
+	pad := []byte{1, 2, 3, 4, 5, 6, 7}
+	data := append(append(append([]byte(nil), src[:extStart]...), pad...), src[extStart:]...)
+	data = patchBase(t, data, func(base *gmstruct.RoleBaseData) {
+		base.ExtBuffOffset += uint32(len(pad))
+		base.DataLen += uint32(len(pad))
+	})
+	assertReencode(t, data)
+
+	// 修改后区块大小变化，填充字节仍然保留在扩展数据之前
+	en = decodeForTest(t, data)
+	if err := en.SetTaskValue(1<<20, 1); err != nil {
+		t.Fatal(err)
+	}
+	out, err := en.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := decodeForTest(t, out)
+	if !bytes.Equal(check.extPadding, pad) {
+		t.Fatalf("ext padding lost : %v", check.extPadding)
+	}
+	if len(DiffRole(en, check)) != 0 {
+		t.Fatalf("modified role differs after re-encode")
+	}
+}
+
+func TestEncodeKeepsExtOffsetWithoutExtData(t *testing.T) {
+	opts := DefaultRoleGenOptions()
+	opts.ExtData = false
+	src, err := NewRoleGenerator(opts).RoleData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有扩展数据时ExtBuffOffset指向CRC32码的位置
+	data := patchBase(t, src, func(base *gmstruct.RoleBaseData) {
+		base.ExtBuffOffset = uint32(len(src) - 4)
+	})
+	assertReencode(t, data)
+}
+
+func TestGeneratorRoundTrip(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		opts := DefaultRoleGenOptions()
+		opts.Seed = seed
+		opts.ExtData = seed%5 != 0
+
+		gen := NewRoleGenerator(opts)
+		role, err := gen.Role()
+		if err != nil {
+			t.Fatalf("seed %d : %v", seed, err)
+		}
+		data, err := role.Encode()
+		if err != nil {
+			t.Fatalf("seed %d : %v", seed, err)
+		}
+
+		en := decodeForTest(t, data)
+		if diffs := DiffRole(role, en); len(diffs) != 0 {
+			t.Fatalf("seed %d : decoded role differs from generated role : %s", seed, diffs[0])
+		}
+
+		out, err := en.Encode()
+		if err != nil {
+			t.Fatalf("seed %d : %v", seed, err)
+		}
+		if !bytes.Equal(out, data) {
+			t.Fatalf("seed %d : re-encoded data differs", seed)
+		}
+	}
+}
+
+func TestGeneratorBakRoundTrip(t *testing.T) {
+	data, err := NewRoleGenerator(DefaultRoleGenOptions()).BakData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bak := NewRoleBakEncoder()
+	bak.SetLogger(quietLog)
+	if !bak.Decode(data) {
+		t.Fatal("decode bak failed")
+	}
+	if bak.CRC32Cal != bak.CRC32Read {
+		t.Fatalf("CRC32 mismatch : cal = %08x, read = %08x", bak.CRC32Cal, bak.CRC32Read)
+	}
+
+	out, err := bak.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Fatal("re-encoded bak data differs")
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	for seed := int64(1); seed <= 5; seed++ {
+		opts := DefaultRoleGenOptions()
+		opts.Seed = seed
+		opts.Items = 5
+		opts.Tasks = 10
+
+		gen := NewRoleGenerator(opts)
+		data, err := gen.RoleData()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+
+		if data, err = gen.BakData(); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// 任意输入都不能使解码器崩溃，解码成功的数据重新编码后必须可以再次解码
+		en := NewRoleEncoder()
+		en.SetLogger(quietLog)
+		if !en.Decode(data) {
+			return
+		}
+
+		out, err := en.Encode()
+		if err != nil {
+			return
+		}
+		check := NewRoleEncoder()
+		check.SetLogger(quietLog)
+		if !check.Decode(out) {
+			t.Fatal("re-encoded data cannot be decoded")
+		}
+	})
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data := generateForTest(b, 1)
+	en := NewRoleEncoder()
+	en.SetLogger(quietLog)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !en.Decode(data) {
+			b.Fatal("decode failed")
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	en := decodeForTest(b, generateForTest(b, 1))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := en.Encode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/heartchord/jxonline/gamecatalog"
 	gmstruct "github.com/heartchord/jxonline/gamestruct"
 )
 
@@ -37,12 +38,39 @@ type RoleEncoder struct {
 	FeatureInfo        []gmstruct.FeatureInfo        // 角色外观数据
 	PlayerEvent        []gmstruct.PlayerEvent        // 角色事件数据
 	PlayerTitle        []gmstruct.RoleTitle          // 角色称号数据
+	MaxSkillLevel      []gmstruct.MaxSkillLevelInfo  // 技能等级上限数据
 	CustomStructHeader []gmstruct.CustomDataHeader   // 自定义数据头
+	CustomStructData   [][]byte                      // 自定义数据原始内容（包含状态类型），编码时原样写回
 	RoleExtData        gmstruct.RoleExtData          // 角色扩展数据
 	CRC32Cal           uint32                        // 根据角色原始数据计算的CRC32码
 	CRC32Read          uint32                        // 从角色原始数据末尾读的CRC32码
 	logger             LogWriter                     // 日志函数
 	extDataReader      map[int32]playerExtDataReader // 角色扩展数据解析函数
+	itemCatalog        gamecatalog.ItemCatalog       // 物品配置查询服务
+	skillCatalog       gamecatalog.SkillCatalog      // 技能配置查询服务
+	taskCatalog        gamecatalog.TaskCatalog       // 任务变量配置查询服务
+	mapCatalog         gamecatalog.MapCatalog        // 地图配置查询服务
+	stateOrder         []byte                        // 状态数据的原始排列顺序，编码时保持不变
+	extDataHeads       []gmstruct.DataHead           // 角色扩展数据头的原始排列顺序，编码时保持不变
+	dataLenPad         int64                         // RoleBaseData.DataLen与原始数据长度的差值
+	decodedLayout      *roleLayout                   // 解码时的数据区块布局，区块大小不变时编码保持原始偏移
+	extPadding         []byte                        // 状态数据末尾到ExtBuffOffset之间的原始字节，编码时原样写回
+	tailPadding        []byte                        // 扩展数据末尾到CRC32码之间的原始字节，编码时原样写回
+	purgedTitles       []gmstruct.RoleTitle          // 上次编码时清除的过期称号
+}
+
+// roleLayout : 角色数据各区块的字节数和RoleBaseData中记录的偏移
+type roleLayout struct {
+	sizes   [6]int    // 战斗技能、生活技能、任务变量、物品、状态、扩展数据的字节数
+	offsets [6]uint32 // FSkillOffset、LSkillOffset、TaskOffset、ItemOffset、StateOffset、ExtBuffOffset
+}
+
+// NewRoleEncoder : 创建一个RoleEncoder对象，用于解码和编码数据库中的角色数据
+func NewRoleEncoder() (en *RoleEncoder) {
+	en = new(RoleEncoder)
+	en.Init()
+	en.SetLogger(fmt.Printf)
+	return en
 }
 
 // Init : 123
@@ -65,10 +93,47 @@ func (en *RoleEncoder) SetLogger(logger LogWriter) {
 	en.logger = logger
 }
 
+// SetItemCatalog : 设置物品配置查询服务，用于输出和导出物品名称
+func (en *RoleEncoder) SetItemCatalog(catalog gamecatalog.ItemCatalog) {
+	en.itemCatalog = catalog
+}
+
+// SetSkillCatalog : 设置技能配置查询服务，用于输出技能名称
+func (en *RoleEncoder) SetSkillCatalog(catalog gamecatalog.SkillCatalog) {
+	en.skillCatalog = catalog
+}
+
+// SetTaskCatalog : 设置任务变量配置查询服务，用于输出、比较和导出任务变量的含义
+func (en *RoleEncoder) SetTaskCatalog(catalog gamecatalog.TaskCatalog) {
+	en.taskCatalog = catalog
+}
+
+// SetMapCatalog : 设置地图配置查询服务，用于输出和导出重生点及登出地图名称
+func (en *RoleEncoder) SetMapCatalog(catalog gamecatalog.MapCatalog) {
+	en.mapCatalog = catalog
+}
+
 // Decode : function to decode original role bak data
-func (en *RoleEncoder) Decode(data []byte) bool {
+func (en *RoleEncoder) Decode(data []byte) (ok bool) {
 	current := uint32(0)
 
+	// 异常数据中的数量和长度可能导致越界访问，视为解码失败
+	defer func() {
+		if r := recover(); r != nil {
+			en.logger("RoleEncoder.Decode : bad role data - %v\n", r)
+			ok = false
+		}
+	}()
+
+	// 清除上次解码的数据
+	en.reset()
+	if len(data) < 4 {
+		return false
+	}
+
+	// 限制切片容量，避免异常数据越过数据末尾读取到底层数组的其他内容（如Bak数据中的角色数据）
+	data = data[:len(data):len(data)]
+
 	// 计算CRC32
 	dataLen := len(data)
 	en.CRC32Cal = CRC32(0, data[:dataLen-4])
@@ -84,12 +149,17 @@ func (en *RoleEncoder) Decode(data []byte) bool {
 
 	en.logger("CurrentPos = %-4d, SkillOffset = %-4d\n", current, en.RoleBaseData.FSkillOffset)
 
+	// 记录各数据区块的起始位置
+	var pos [7]uint32
+	pos[0] = current
+
 	// 角色战斗技能解码
 	if !en.decodeRoleFSkillData(data, &current) {
 		return false
 	}
 
 	en.logger("CurrentPos = %-4d, LSkillOffset = %-4d\n", current, en.RoleBaseData.LSkillOffset)
+	pos[1] = current
 
 	// 角色生活技能解码
 	if !en.decodeRoleLSkillData(data, &current) {
@@ -97,6 +167,7 @@ func (en *RoleEncoder) Decode(data []byte) bool {
 	}
 
 	en.logger("CurrentPos = %-4d, TaskOffset = %-4d\n", current, en.RoleBaseData.TaskOffset)
+	pos[2] = current
 
 	// 角色任务变量解码
 	if !en.decodeRoleTaskData(data, &current) {
@@ -104,6 +175,7 @@ func (en *RoleEncoder) Decode(data []byte) bool {
 	}
 
 	en.logger("CurrentPos = %-4d, ItemOffset = %-4d\n", current, en.RoleBaseData.ItemOffset)
+	pos[3] = current
 
 	// 角色装备道具解码
 	if !en.decodeRoleItemData(data, &current) {
@@ -111,18 +183,36 @@ func (en *RoleEncoder) Decode(data []byte) bool {
 	}
 
 	en.logger("CurrentPos = %-4d, StateOffset = %-4d\n", current, en.RoleBaseData.StateOffset)
+	pos[4] = current
 
 	if !en.decodeRoleStateList(data, &current) {
 		return false
 	}
 
 	en.logger("CurrentPos = %-4d, ExtBuffOffset = %-4d\n", current, en.RoleBaseData.ExtBuffOffset)
+	pos[5] = current
+
+	// 扩展数据从ExtBuffOffset开始解码，中间的字节保存下来编码时写回
+	extStart := current
+	if en.RoleBaseData.ExtBuffOffset > 0 && en.RoleBaseData.ExtBuffOffset != current {
+		extStart = en.RoleBaseData.ExtBuffOffset
+	}
+	if extStart > current && extStart <= uint32(dataLen-4) {
+		en.extPadding = append([]byte(nil), data[current:extStart]...)
+	}
 
 	if !en.decodeRoleExtData(data, &current) {
 		return false
 	}
 
+	pos[6] = current
+	if current < uint32(dataLen-4) {
+		en.tailPadding = append([]byte(nil), data[current:dataLen-4]...)
+	}
+	en.decodedLayout = en.recordLayout(pos, extStart)
+
 	en.logger("CurrentPos = %-4d, RoleDataLen = %-4d\n", current, en.RoleBaseData.DataLen)
+	en.dataLenPad = int64(en.RoleBaseData.DataLen) - int64(dataLen)
 
 	return true
 }
@@ -134,6 +224,18 @@ func (en RoleEncoder) PrintAllTaskData() {
 	fmt.Println("=================================[TASK VALUE]=================================")
 	fmt.Printf("Total = %d\n", count)
 
+	// 设置了任务变量配置时每行输出一个任务变量及其含义
+	if en.taskCatalog != nil {
+		for i := 0; i < count; i++ {
+			fmt.Printf("Task[ %-4d ] = %-10d", en.TaskData[i].TaskID, en.TaskData[i].TaskValue)
+			if text := gamecatalog.DescribeTask(en.taskCatalog, int(en.TaskData[i].TaskID), en.TaskData[i].TaskValue); text != "" {
+				fmt.Printf(" %s", text)
+			}
+			fmt.Println("")
+		}
+		return
+	}
+
 	for i := 0; i < count; i++ {
 		fmt.Printf("Task[ %-4d ] = %-10d", en.TaskData[i].TaskID, en.TaskData[i].TaskValue)
 		if i%2 == 1 {
@@ -155,6 +257,9 @@ func (en RoleEncoder) PrintAllFSkillData() {
 
 	for i := 0; i < count; i++ {
 		fmt.Printf("Skill[ %-4d ] = { %-2d, %-10d }", en.FSkillData[i].SkillID, en.FSkillData[i].SkillLv, en.FSkillData[i].SkillExp)
+		if name := gamecatalog.SkillName(en.skillCatalog, int(en.FSkillData[i].SkillID)); name != "" {
+			fmt.Printf(" %s", name)
+		}
 		if i%2 == 1 {
 			fmt.Println("")
 		}
@@ -174,6 +279,9 @@ func (en RoleEncoder) PrintAllLSkillData() {
 
 	for i := 0; i < count; i++ {
 		fmt.Printf("Skill[ %-4d ] = { %-2d, %-10d }", en.LSkillData[i].SkillID, en.LSkillData[i].SkillLv, en.LSkillData[i].SkillExp)
+		if name := gamecatalog.SkillName(en.skillCatalog, int(en.LSkillData[i].SkillID)); name != "" {
+			fmt.Printf(" %s", name)
+		}
 		if i%2 == 1 {
 			fmt.Println("")
 		}
@@ -192,10 +300,15 @@ func (en RoleEncoder) PrintAllItemData() {
 	fmt.Printf("Total = %d\n", count)
 
 	for i := 0; i < count; i++ {
-		fmt.Printf("Item[ %-3d ] = { G = %d, D = %d, P = %-4d, Lv = %-2d, Place = %-2d }\n", i,
+		fmt.Printf("Item[ %-3d ] = { G = %d, D = %d, P = %-4d, Lv = %-2d, Place = %-2d }", i,
 			en.ItemData[i].Standard.ClassCode&0x0000FFFF, en.ItemData[i].Standard.DetailType, en.ItemData[i].Standard.ParticularType,
 			en.ItemData[i].Standard.Level, en.ItemData[i].Standard.Place)
 
+		if name := gamecatalog.ItemName(en.itemCatalog, &en.ItemData[i]); name != "" {
+			fmt.Printf(" %s", name)
+		}
+		fmt.Print("\n")
+
 		if i%20 == 19 {
 			reader := bufio.NewReader(os.Stdin)
 			reader.ReadLine()
@@ -209,6 +322,56 @@ func (en RoleEncoder) PrintAllSkillData() {
 	en.PrintAllLSkillData()
 }
 
+// PrintRoleLocation : function to print revival and logout location
+func (en RoleEncoder) PrintRoleLocation() {
+	base := &en.RoleBaseData
+
+	fmt.Println("================================[ROLE LOCATION]===============================")
+	fmt.Printf("Revival = { Map : %-4d, Point : %-4d } %s\n", base.RevivalID, base.RevivalX, gamecatalog.MapName(en.mapCatalog, int(base.RevivalID)))
+	fmt.Printf("Logout  = { Map : %-4d, X : %-6d, Y : %-6d } %s\n", base.SubWorldID, base.SubWorldMpsX, base.SubWorldMpsY, gamecatalog.MapName(en.mapCatalog, int(base.SubWorldID)))
+	fmt.Printf("MapCopyIndex = %d\n", base.MapCopyIndex)
+}
+
+// recordLayout : 根据各数据区块的起始位置记录解码时的布局
+func (en *RoleEncoder) recordLayout(pos [7]uint32, extStart uint32) *roleLayout {
+	base := &en.RoleBaseData
+	l := &roleLayout{
+		offsets: [6]uint32{base.FSkillOffset, base.LSkillOffset, base.TaskOffset, base.ItemOffset, base.StateOffset, base.ExtBuffOffset},
+	}
+	for i := 0; i < 5; i++ {
+		l.sizes[i] = int(pos[i+1]) - int(pos[i])
+	}
+	if pos[6] > extStart {
+		l.sizes[5] = int(pos[6] - extStart)
+	}
+	return l
+}
+
+// reset : 清除已解码的角色数据，解码器重复使用时避免残留上次的数据
+func (en *RoleEncoder) reset() {
+	en.RoleBaseData = gmstruct.RoleBaseData{}
+	en.FSkillData = nil
+	en.LSkillData = nil
+	en.TaskData = nil
+	en.ItemData = nil
+	en.SkillState = nil
+	en.SkillCD = nil
+	en.FeatureInfo = nil
+	en.PlayerEvent = nil
+	en.PlayerTitle = nil
+	en.MaxSkillLevel = nil
+	en.CustomStructHeader = nil
+	en.CustomStructData = nil
+	en.RoleExtData = gmstruct.RoleExtData{}
+	en.stateOrder = nil
+	en.extDataHeads = nil
+	en.dataLenPad = 0
+	en.decodedLayout = nil
+	en.extPadding = nil
+	en.tailPadding = nil
+	en.purgedTitles = nil
+}
+
 func (en *RoleEncoder) decodeRoleBaseInfo(data []byte, current *uint32) bool {
 
 	dataLen := uint32(len(data))
@@ -239,10 +402,15 @@ func (en *RoleEncoder) decodeRoleFSkillData(data []byte, current *uint32) bool {
 	if skillCount == 0 {
 		return true
 	}
-	en.FSkillData = make([]gmstruct.SkillData, skillCount)
 
 	start := *current
 	dataLen := uint32(len(data))
+
+	// 先检查数据长度再分配，避免偏移异常时分配过大的内存
+	if uint64(start)+uint64(skillCount)*uint64(binary.Size(gmstruct.SkillData{})) > uint64(dataLen) {
+		return false
+	}
+	en.FSkillData = make([]gmstruct.SkillData, skillCount)
 	totalLen := uint32(binary.Size(en.FSkillData))
 
 	if start+totalLen > dataLen { // 数据长度 < 技能数据长度
@@ -271,10 +439,15 @@ func (en *RoleEncoder) decodeRoleLSkillData(data []byte, current *uint32) bool {
 	if skillCount == 0 {
 		return true
 	}
-	en.LSkillData = make([]gmstruct.SkillData, skillCount)
 
 	start := *current
 	dataLen := uint32(len(data))
+
+	// 先检查数据长度再分配，避免偏移异常时分配过大的内存
+	if uint64(start)+uint64(skillCount)*uint64(binary.Size(gmstruct.SkillData{})) > uint64(dataLen) {
+		return false
+	}
+	en.LSkillData = make([]gmstruct.SkillData, skillCount)
 	totalLen := uint32(binary.Size(en.LSkillData))
 
 	if start+totalLen > dataLen { // 数据长度 < 技能数据长度
@@ -304,10 +477,15 @@ func (en *RoleEncoder) decodeRoleTaskData(data []byte, current *uint32) bool {
 	if taskCount == 0 {
 		return true
 	}
-	en.TaskData = make([]gmstruct.TaskData, taskCount)
 
 	start := *current
 	dataLen := uint32(len(data))
+
+	// 先检查数据长度再分配，避免偏移异常时分配过大的内存
+	if uint64(start)+uint64(taskCount)*uint64(binary.Size(gmstruct.TaskData{})) > uint64(dataLen) {
+		return false
+	}
+	en.TaskData = make([]gmstruct.TaskData, taskCount)
 	totalLen := uint32(binary.Size(en.TaskData))
 
 	if start+totalLen > dataLen { // 数据长度 < 任务变量数据长度
@@ -415,6 +593,7 @@ func (en *RoleEncoder) decodeRoleStateList(data []byte, current *uint32) bool {
 		end = start + stateDataLen
 		buf := bytes.NewBuffer(data[start:end])
 		binary.Read(buf, binary.LittleEndian, &stateData)
+		en.stateOrder = append(en.stateOrder, stateData.Type)
 
 		// 根据类型进行解码
 		switch stateData.Type {
@@ -470,6 +649,17 @@ func (en *RoleEncoder) decodeRoleStateList(data []byte, current *uint32) bool {
 				binary.Read(buf, binary.LittleEndian, &title)
 				en.PlayerTitle = append(en.PlayerTitle, title)
 
+				start += stateDataLen
+				*current += stateDataLen
+			}
+		case gmstruct.PlayerMaxSkillLevelType:
+			{
+				var info gmstruct.MaxSkillLevelInfo
+				structLen := uint32(binary.Size(info))
+				buf := bytes.NewBuffer(stateData.Data[0:structLen])
+				binary.Read(buf, binary.LittleEndian, &info)
+				en.MaxSkillLevel = append(en.MaxSkillLevel, info)
+
 				start += stateDataLen
 				*current += stateDataLen
 			}
@@ -482,6 +672,7 @@ func (en *RoleEncoder) decodeRoleStateList(data []byte, current *uint32) bool {
 				buf := bytes.NewBuffer(stateData.Data[0:structLen])
 				binary.Read(buf, binary.LittleEndian, &custom)
 				en.CustomStructHeader = append(en.CustomStructHeader, custom)
+				en.CustomStructData = append(en.CustomStructData, append([]byte(nil), data[start:start+1+custom.Size]...))
 
 				// 处理用户自定义数据体
 				switch custom.Type {
@@ -566,6 +757,7 @@ func (en *RoleEncoder) decodeRoleExtData(data []byte, current *uint32) bool {
 		if t >= roleExtDataTypeCount {
 			return false
 		}
+		en.extDataHeads = append(en.extDataHeads, header)
 
 		// 解析角色扩展数据
 		if !en.extDataReader[t](data, current) {
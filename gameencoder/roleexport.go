@@ -0,0 +1,156 @@
+package gameencoder
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// RoleExportOptions : 角色数据导出选项
+type RoleExportOptions struct {
+	ExpTable gmstruct.LevelExpTable // 等级经验表，为空时不导出升级进度
+}
+
+// RoleExportData : 角色数据导出格式，经验等拆分存储的字段以组合后的值导出
+type RoleExportData struct {
+	RoleBaseData   gmstruct.RoleBaseData        // 角色基础数据
+	Exp            int64                        // 角色经验（ExpHigh和ExpLow组合值）
+	RevivalMap     string                       `json:",omitempty"` // 重生点地图名称
+	SubWorldMap    string                       `json:",omitempty"` // 上次登出地图名称
+	LevelProgress  *float64                     `json:",omitempty"` // 当前等级升级进度
+	TransNimbusExp *int64                       `json:",omitempty"` // 转灵经验（TransNimbusExpHigh和TransNimbusExpLow组合值）
+	FSkillData     []gmstruct.SkillData         // 战斗技能数据
+	LSkillData     []gmstruct.SkillData         // 生活技能数据
+	TaskData       []RoleExportTask             // 任务变量数据
+	ItemData       []RoleExportItem             // 装备物品数据
+	SkillState     []gmstruct.SkillState        // 技能状态数据
+	SkillCD        []gmstruct.SkillCD           // 技能冷却数据
+	FeatureInfo    []gmstruct.FeatureInfo       // 角色外观数据
+	PlayerEvent    []gmstruct.PlayerEvent       // 角色事件数据
+	PlayerTitle    []gmstruct.RoleTitle         // 角色称号数据
+	MaxSkillLevel  []gmstruct.MaxSkillLevelInfo // 技能等级上限数据
+	RoleExtData    gmstruct.RoleExtData         // 角色扩展数据
+}
+
+// RoleExportItem : 物品导出格式，设置了物品配置查询服务时包含物品名称
+type RoleExportItem struct {
+	gmstruct.ItemData
+	Name         string `json:",omitempty"` // 物品名称
+	RequireLevel int    `json:",omitempty"` // 需求等级
+}
+
+// RoleExportTask : 任务变量导出格式，设置了任务变量配置查询服务时包含变量名称和含义
+type RoleExportTask struct {
+	gmstruct.TaskData
+	Name    string `json:",omitempty"` // 任务变量名称
+	Group   string `json:",omitempty"` // 所属任务分组
+	Meaning string `json:",omitempty"` // 任务变量值的含义
+}
+
+// Export : function to build exported data of decoded role
+func (en *RoleEncoder) Export(opts *RoleExportOptions) *RoleExportData {
+	data := &RoleExportData{
+		RoleBaseData:  en.RoleBaseData,
+		Exp:           en.RoleBaseData.Exp(),
+		RevivalMap:    gamecatalog.MapName(en.mapCatalog, int(en.RoleBaseData.RevivalID)),
+		SubWorldMap:   gamecatalog.MapName(en.mapCatalog, int(en.RoleBaseData.SubWorldID)),
+		FSkillData:    en.FSkillData,
+		LSkillData:    en.LSkillData,
+		SkillState:    en.SkillState,
+		SkillCD:       en.SkillCD,
+		FeatureInfo:   en.FeatureInfo,
+		PlayerEvent:   en.PlayerEvent,
+		PlayerTitle:   en.PlayerTitle,
+		MaxSkillLevel: en.MaxSkillLevel,
+		RoleExtData:   en.RoleExtData,
+	}
+
+	for i := range en.ItemData {
+		item := RoleExportItem{ItemData: en.ItemData[i]}
+		if info, ok := gamecatalog.LookupItemData(en.itemCatalog, &en.ItemData[i]); ok {
+			item.Name = info.Name
+			item.RequireLevel = info.RequireLevel
+		}
+		data.ItemData = append(data.ItemData, item)
+	}
+
+	for _, task := range en.TaskData {
+		item := RoleExportTask{TaskData: task}
+		if info, ok := catalogTask(en.taskCatalog, int(task.TaskID)); ok {
+			item.Name = info.Name
+			item.Group = info.Group
+			item.Meaning = info.Decode(task.TaskValue)
+		}
+		data.TaskData = append(data.TaskData, item)
+	}
+
+	if opts != nil && opts.ExpTable != nil {
+		if progress, ok := en.RoleBaseData.LevelProgress(opts.ExpTable); ok {
+			data.LevelProgress = &progress
+		}
+	}
+
+	if en.RoleExtData.HasTransNimbus {
+		exp := en.RoleExtData.TransNimbus.Exp()
+		data.TransNimbusExp = &exp
+	}
+
+	return data
+}
+
+// ExportJSON : function to export decoded role as JSON
+func (en *RoleEncoder) ExportJSON(w io.Writer, opts *RoleExportOptions) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(en.Export(opts))
+}
+
+// ExportItemsCSV : function to export all items of decoded role as CSV
+func (en *RoleEncoder) ExportItemsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"Index", "G", "D", "P", "Name", "Level", "Series", "Place", "PosX", "PosY", "BindFlag", "ItemGUID", "Owner"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i := range en.ItemData {
+		item := &en.ItemData[i]
+		std := &item.Standard
+
+		guid := int64(0)
+		owner := ""
+		if item.HasLockSoul {
+			guid = item.LockSoul.ItemGUID
+			owner = item.LockSoul.Owner.String()
+		} else if item.HasBill {
+			guid = item.Bill.ItemGUID
+		}
+
+		record := []string{
+			fmt.Sprintf("%d", i),
+			fmt.Sprintf("%d", std.ClassCode&0x0000FFFF),
+			fmt.Sprintf("%d", std.DetailType),
+			fmt.Sprintf("%d", std.ParticularType),
+			gamecatalog.ItemName(en.itemCatalog, item),
+			fmt.Sprintf("%d", std.Level),
+			std.Series.String(),
+			fmt.Sprintf("%d", std.Place),
+			fmt.Sprintf("%d", std.PosX),
+			fmt.Sprintf("%d", std.PosY),
+			fmt.Sprintf("%d", std.BindFlag),
+			fmt.Sprintf("%d", guid),
+			owner,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
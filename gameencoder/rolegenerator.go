@@ -0,0 +1,236 @@
+package gameencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// RoleGenOptions : 随机角色生成选项
+type RoleGenOptions struct {
+	Seed          int64 // 随机数种子，相同种子生成相同的角色
+	FSkills       int   // 战斗技能数量
+	LSkills       int   // 生活技能数量
+	Tasks         int   // 任务变量数量
+	Items         int   // 物品数量，物品数据组成依次使用标准、锁魂、账单、扩展数据的所有组合
+	StatesPerType int   // 每种状态数据的数量（包含同伴自定义数据）
+	ExtData       bool  // 是否生成所有类型的角色扩展数据
+}
+
+// DefaultRoleGenOptions : 默认的随机角色生成选项
+func DefaultRoleGenOptions() RoleGenOptions {
+	return RoleGenOptions{
+		Seed:          1,
+		FSkills:       20,
+		LSkills:       5,
+		Tasks:         200,
+		Items:         30,
+		StatesPerType: 2,
+		ExtData:       true,
+	}
+}
+
+// RoleGenerator : 随机角色生成器，用于测试、模糊测试种子和性能测试
+type RoleGenerator struct {
+	opts RoleGenOptions
+	rnd  *rand.Rand
+}
+
+// NewRoleGenerator : 创建一个RoleGenerator对象
+func NewRoleGenerator(opts RoleGenOptions) *RoleGenerator {
+	return &RoleGenerator{opts: opts, rnd: rand.New(rand.NewSource(opts.Seed))}
+}
+
+// fill : 使用随机字节填充结构体
+func (g *RoleGenerator) fill(v interface{}) {
+	b := make([]byte, binary.Size(v))
+	g.rnd.Read(b)
+	binary.Read(bytes.NewReader(b), binary.LittleEndian, v)
+}
+
+// name : 生成一个随机ASCII名称
+func (g *RoleGenerator) name(prefix string) gmstruct.GBKName {
+	var n gmstruct.GBKName
+	n.Set(fmt.Sprintf("%s%06d", prefix, g.rnd.Intn(1000000)))
+	return n
+}
+
+// Role : function to generate a random role whose counts and offsets are consistent
+func (g *RoleGenerator) Role() (*RoleEncoder, error) {
+	en := NewRoleEncoder()
+	en.SetLogger(func(format string, a ...interface{}) (int, error) { return 0, nil })
+
+	g.genBaseData(en)
+	g.genSkillAndTaskData(en)
+	g.genItemData(en)
+	g.genStateData(en)
+	if g.opts.ExtData {
+		g.genExtData(en)
+	}
+
+	if err := en.UpdateLayout(); err != nil {
+		return nil, err
+	}
+	return en, nil
+}
+
+// RoleData : function to generate random DB role data
+func (g *RoleGenerator) RoleData() ([]byte, error) {
+	en, err := g.Role()
+	if err != nil {
+		return nil, err
+	}
+	return en.Encode()
+}
+
+// BakData : function to generate random role bak data
+func (g *RoleGenerator) BakData() ([]byte, error) {
+	en, err := g.Role()
+	if err != nil {
+		return nil, err
+	}
+
+	bak := NewRoleBakEncoder()
+	bak.RoleEncoder = *en
+	bak.RoleEncoder.Init()
+	return bak.Encode()
+}
+
+func (g *RoleGenerator) genBaseData(en *RoleEncoder) {
+	base := &en.RoleBaseData
+	g.fill(&base.RoleBaseInfo)
+
+	base.RoleName = g.name("Role")
+	base.Alias = gmstruct.GBKName{}
+	base.Account = g.name("Acc")
+	base.PrimaryKey.Set(fmt.Sprintf("%032x", g.rnd.Uint64()))
+
+	// 枚举字段使用有效值
+	base.Sex = gmstruct.Sex(g.rnd.Intn(2))
+	base.LastFaction = gmstruct.Faction(g.rnd.Intn(11))
+	base.CurFaction = base.LastFaction
+	base.FiveElement = gmstruct.Series(g.rnd.Intn(5))
+	base.Camp = gmstruct.Camp(g.rnd.Intn(int(gmstruct.CampFree) + 1)) // 玩家角色使用的阵营
+	base.PkStatus = gmstruct.PkStatus(g.rnd.Intn(3))
+
+	base.RoleLevel = uint16(1 + g.rnd.Intn(200))
+	base.SetExp(g.rnd.Int63n(gmstruct.MaxRoleExp))
+	base.BagMoney = g.rnd.Int31()
+	base.BoxMoney = g.rnd.Int31()
+}
+
+func (g *RoleGenerator) genSkillAndTaskData(en *RoleEncoder) {
+	for i := 0; i < g.opts.FSkills; i++ {
+		en.FSkillData = append(en.FSkillData, gmstruct.SkillData{SkillID: int16(1 + i), SkillLv: int16(1 + g.rnd.Intn(20)), SkillExp: g.rnd.Uint32()})
+	}
+	for i := 0; i < g.opts.LSkills; i++ {
+		en.LSkillData = append(en.LSkillData, gmstruct.SkillData{SkillID: int16(1 + i), SkillLv: int16(1 + g.rnd.Intn(20)), SkillExp: g.rnd.Uint32()})
+	}
+	for i := 0; i < g.opts.Tasks; i++ {
+		en.TaskData = append(en.TaskData, gmstruct.TaskData{TaskID: int32(1 + i), TaskValue: g.rnd.Int31()})
+	}
+}
+
+func (g *RoleGenerator) genItemData(en *RoleEncoder) {
+	for i := 0; i < g.opts.Items; i++ {
+		// 依次使用1~15的组成标志
+		flags := int32(i%15 + 1)
+
+		item := gmstruct.ItemData{
+			HasStandard: flags&itemDataFlagOfStandard != 0,
+			HasLockSoul: flags&itemDataFlagOfLockSoul != 0,
+			HasBill:     flags&itemDataFlagOfBill != 0,
+			HasExtend:   flags&itemDataFlagOfExtend != 0,
+		}
+
+		if item.HasStandard {
+			g.fill(&item.Standard)
+			item.Standard.Series = gmstruct.Series(g.rnd.Intn(5))
+		}
+		if item.HasLockSoul {
+			g.fill(&item.LockSoul)
+			item.LockSoul.Owner = en.RoleBaseData.RoleName
+			item.LockSoul.ItemGUID = int64(i + 1)
+		}
+		if item.HasBill {
+			g.fill(&item.Bill)
+			item.Bill.ItemGUID = int64(i + 1)
+		}
+		if item.HasExtend {
+			g.fill(&item.Extend)
+			item.Extend.OwnerName = gmstruct.GBKName{}
+		}
+
+		en.ItemData = append(en.ItemData, item)
+	}
+}
+
+func (g *RoleGenerator) genStateData(en *RoleEncoder) {
+	for i := 0; i < g.opts.StatesPerType; i++ {
+		var state gmstruct.SkillState
+		g.fill(&state)
+		en.SkillState = append(en.SkillState, state)
+
+		var cd gmstruct.SkillCD
+		g.fill(&cd)
+		en.SkillCD = append(en.SkillCD, cd)
+
+		var feature gmstruct.FeatureInfo
+		g.fill(&feature)
+		en.FeatureInfo = append(en.FeatureInfo, feature)
+
+		var event gmstruct.PlayerEvent
+		g.fill(&event)
+		en.PlayerEvent = append(en.PlayerEvent, event)
+
+		var title gmstruct.RoleTitle
+		g.fill(&title)
+		title.TitleTime.Type = int32(g.rnd.Intn(gmstruct.RoleTitleTypeCount))
+		en.PlayerTitle = append(en.PlayerTitle, title)
+
+		var limit gmstruct.MaxSkillLevelInfo
+		g.fill(&limit)
+		en.MaxSkillLevel = append(en.MaxSkillLevel, limit)
+
+		g.genPartnerData(en)
+	}
+}
+
+// genPartnerData : 生成一个包含一个同伴的同伴自定义数据
+func (g *RoleGenerator) genPartnerData(en *RoleEncoder) {
+	var partner gmstruct.Partner
+	g.fill(&partner)
+
+	var header gmstruct.CustomDataOfPartnerHeader
+	header.Type = gmstruct.CustomDataTypeOfPartner
+	header.Size = uint32(binary.Size(header) + binary.Size(partner))
+	header.PartnerCount = 1
+
+	var buf bytes.Buffer
+	buf.WriteByte(gmstruct.CustomStructType)
+	binary.Write(&buf, binary.LittleEndian, &header)
+	binary.Write(&buf, binary.LittleEndian, &partner)
+
+	en.CustomStructHeader = append(en.CustomStructHeader, header.CustomDataHeader)
+	en.CustomStructData = append(en.CustomStructData, buf.Bytes())
+}
+
+func (g *RoleGenerator) genExtData(en *RoleEncoder) {
+	ext := &en.RoleExtData
+
+	ext.HasBase = true
+	g.fill(&ext.Base)
+	ext.HasLingLongLock = true
+	g.fill(&ext.LingLongLock)
+	ext.HasHangerOn = true
+	g.fill(&ext.HangerOn)
+	ext.HasTransNimbus = true
+	ext.TransNimbus.SetExp(g.rnd.Int63n(gmstruct.MaxRoleExp))
+	ext.HasBreak = true
+	ext.Break.HasBreak = byte(g.rnd.Intn(2))
+	ext.HasEquipCompose = true
+	g.fill(&ext.EquipCompose)
+}
@@ -0,0 +1,216 @@
+package gameencoder
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// ItemSelector : 物品选择条件，各条件同时满足时选中，为空的条件不限制
+type ItemSelector struct {
+	Keys   []gamecatalog.ItemKey // 物品G/D/P，任一匹配即可，字段为-1表示任意值
+	Places []int32               // 物品存储空间
+	GUIDs  []int64               // 物品GUID
+	All    bool                  // 条件都为空时是否选择全部物品，未设置时EditItems拒绝空的选择条件
+}
+
+// Empty : 选择条件是否为空
+func (s *ItemSelector) Empty() bool {
+	return s == nil || (len(s.Keys) == 0 && len(s.Places) == 0 && len(s.GUIDs) == 0)
+}
+
+// matchKey : 物品G/D/P是否匹配，-1表示任意值
+func matchKey(pattern, key gamecatalog.ItemKey) bool {
+	return (pattern.Genre < 0 || pattern.Genre == key.Genre) &&
+		(pattern.DetailType < 0 || pattern.DetailType == key.DetailType) &&
+		(pattern.ParticularType < 0 || pattern.ParticularType == key.ParticularType)
+}
+
+// Match : 物品是否满足选择条件
+func (s *ItemSelector) Match(item *gmstruct.ItemData) bool {
+	if len(s.Keys) > 0 {
+		if !item.HasStandard {
+			return false
+		}
+
+		key := gamecatalog.ItemKeyOf(&item.Standard)
+		matched := false
+		for _, pattern := range s.Keys {
+			if matchKey(pattern, key) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(s.Places) > 0 {
+		matched := false
+		for _, place := range s.Places {
+			if item.HasStandard && item.Standard.Place == place {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(s.GUIDs) > 0 {
+		guid, ok := itemGUID(item)
+		if !ok {
+			return false
+		}
+
+		matched := false
+		for _, g := range s.GUIDs {
+			if g == guid {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SelectItems : function to get indexes of all items matching selector
+func (en *RoleEncoder) SelectItems(sel *ItemSelector) []int {
+	var indexes []int
+	for i := range en.ItemData {
+		if sel.Match(&en.ItemData[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// ItemEditFunc : 物品修改函数，role为物品所属角色
+type ItemEditFunc func(role *RoleEncoder, item *gmstruct.ItemData)
+
+// UnbindItem : 解除物品绑定，没有标准数据的物品不修改
+func UnbindItem() ItemEditFunc {
+	return func(role *RoleEncoder, item *gmstruct.ItemData) {
+		if !item.HasStandard {
+			return
+		}
+
+		item.Standard.BindFlag = 0
+		item.Standard.DeBindTime = 0
+	}
+}
+
+// LockSoulOptions : 物品锁魂选项
+type LockSoulOptions struct {
+	State             byte         // 锁魂状态
+	UnlockExpiredTime uint32       // 解除锁魂的到期时间
+	NewGUID           func() int64 // GUID生成函数，为空时使用随机GUID；预览和实际修改使用相同的生成结果时GUID才一致
+}
+
+// SetLockSoul : 将物品锁魂到所属角色，没有锁魂数据的物品会添加锁魂数据
+// 物品没有GUID时使用账单数据中的GUID或由opts.NewGUID生成GUID
+func SetLockSoul(opts LockSoulOptions) ItemEditFunc {
+	newGUID := opts.NewGUID
+	if newGUID == nil {
+		newGUID = RandomGUID
+	}
+
+	return func(role *RoleEncoder, item *gmstruct.ItemData) {
+		if !item.HasLockSoul {
+			item.HasLockSoul = true
+			item.LockSoul = gmstruct.ItemDataLockSoul{}
+		}
+
+		if item.LockSoul.ItemGUID == 0 {
+			if guid, ok := itemGUID(item); ok {
+				item.LockSoul.ItemGUID = guid
+			} else {
+				item.LockSoul.ItemGUID = newGUID()
+			}
+		}
+
+		item.LockSoul.State = opts.State
+		item.LockSoul.UnLockExpiredTime = opts.UnlockExpiredTime
+		item.LockSoul.Owner = role.RoleBaseData.RoleName
+		if role.RoleExtData.HasBase {
+			item.LockSoul.OwnerGUID = role.RoleExtData.Base.RoleNameGUID
+		}
+	}
+}
+
+// ClearLockSoul : 清除物品锁魂状态和归属人，保留物品GUID
+func ClearLockSoul() ItemEditFunc {
+	return func(role *RoleEncoder, item *gmstruct.ItemData) {
+		if !item.HasLockSoul {
+			return
+		}
+
+		item.LockSoul.State = 0
+		item.LockSoul.UnLockExpiredTime = 0
+		item.LockSoul.Owner = gmstruct.GBKName{}
+		item.LockSoul.OwnerGUID = 0
+	}
+}
+
+// ItemEditResult : 批量修改物品的结果
+type ItemEditResult struct {
+	DryRun  bool            // 是否只预览不修改
+	Indexes []int           // 选中的物品索引
+	Listing []string        // 选中的物品列表
+	Diffs   []RoleDiffEntry // 修改的字段
+}
+
+// EditItems : function to apply edit to all items matching selector
+// dryRun为true时只返回选中的物品和将要修改的字段，角色数据保持不变
+// 修改在角色副本上进行，成功后才替换原角色
+func (en *RoleEncoder) EditItems(sel *ItemSelector, edit ItemEditFunc, dryRun bool) (*ItemEditResult, error) {
+	if sel == nil || (sel.Empty() && !sel.All) {
+		return nil, fmt.Errorf("gameencoder: empty item selector, set All to edit all items")
+	}
+
+	result := &ItemEditResult{DryRun: dryRun, Indexes: en.SelectItems(sel)}
+
+	role := en.Clone()
+
+	for _, i := range result.Indexes {
+		old := role.ItemData[i]
+		key := itemDiffKey(old, i)
+		result.Listing = append(result.Listing, fmt.Sprintf("Item[%s] %s", key, formatItemSummary(old, en.itemCatalog)))
+
+		edit(role, &role.ItemData[i])
+		result.Diffs = append(result.Diffs, diffStructFields("Item", fmt.Sprintf("Item[%s].", key),
+			reflect.ValueOf(old), reflect.ValueOf(role.ItemData[i]), nil)...)
+	}
+
+	// 添加锁魂数据会改变物品数据长度
+	if err := role.UpdateLayout(); err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		*en = *role
+		en.Init()
+	}
+	return result, nil
+}
+
+// PrintItemEditResult : function to print result of batch item edit
+func PrintItemEditResult(result *ItemEditResult) {
+	fmt.Println("=================================[ITEM EDIT]==================================")
+	fmt.Printf("DryRun = %v, Selected = %d, Changes = %d\n", result.DryRun, len(result.Indexes), len(result.Diffs))
+
+	for _, line := range result.Listing {
+		fmt.Println(line)
+	}
+	for _, d := range result.Diffs {
+		fmt.Println(d.String())
+	}
+}
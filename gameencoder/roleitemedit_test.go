@@ -0,0 +1,87 @@
+package gameencoder
+
+import (
+	"testing"
+)
+
+func TestEditItemsRejectsEmptySelector(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+
+	if _, err := en.EditItems(nil, UnbindItem(), true); err == nil {
+		t.Fatal("nil selector accepted")
+	}
+	if _, err := en.EditItems(&ItemSelector{}, UnbindItem(), true); err == nil {
+		t.Fatal("empty selector accepted")
+	}
+
+	result, err := en.EditItems(&ItemSelector{All: true}, UnbindItem(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Indexes) != len(en.ItemData) {
+		t.Fatalf("All selected %d of %d items", len(result.Indexes), len(en.ItemData))
+	}
+}
+
+func TestEditItemsUnbind(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+
+	// 选择第一个有标准数据的物品所在的存储空间
+	place, found := int32(0), false
+	for i := range en.ItemData {
+		if en.ItemData[i].HasStandard {
+			place, found = en.ItemData[i].Standard.Place, true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("generated role has no standard item")
+	}
+
+	result, err := en.EditItems(&ItemSelector{Places: []int32{place}}, UnbindItem(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Indexes) == 0 {
+		t.Fatal("no item selected")
+	}
+	for _, i := range result.Indexes {
+		if item := &en.ItemData[i]; item.Standard.BindFlag != 0 || item.Standard.DeBindTime != 0 {
+			t.Fatalf("item %d still bound", i)
+		}
+	}
+}
+
+func TestEditItemsLockSoulPreviewMatchesRun(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	sel := &ItemSelector{All: true}
+
+	// 使用确定的GUID生成函数时预览和实际修改的结果一致
+	edit := func() ItemEditFunc {
+		next := int64(1000)
+		return SetLockSoul(LockSoulOptions{State: 1, NewGUID: func() int64 { next++; return next }})
+	}
+
+	preview, err := en.EditItems(sel, edit(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := en.EditItems(sel, edit(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(preview.Diffs) != len(result.Diffs) {
+		t.Fatalf("preview has %d changes, run has %d", len(preview.Diffs), len(result.Diffs))
+	}
+	for i := range preview.Diffs {
+		if preview.Diffs[i] != result.Diffs[i] {
+			t.Fatalf("preview differs from run : %s != %s", preview.Diffs[i], result.Diffs[i])
+		}
+	}
+	for i := range en.ItemData {
+		if !en.ItemData[i].HasLockSoul || en.ItemData[i].LockSoul.State != 1 {
+			t.Fatalf("item %d not locked", i)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package gameencoder
+
+import (
+	"fmt"
+
+	"github.com/heartchord/jxonline/gameinventory"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// ItemMoveOptions : 角色间移动物品选项
+type ItemMoveOptions struct {
+	Places       []int32                // 目标角色中查找空闲位置的容器顺序，必须与加载的容器布局对应，不能为空
+	Inventory    *gameinventory.Options // 物品布局选项，必须包含gameinventory.LoadContainers加载的容器布局
+	RewriteOwner bool                   // 是否将锁魂归属人和装备所有者改为目标角色
+}
+
+// ItemMoveResult : 角色间移动物品的结果
+type ItemMoveResult struct {
+	Item   gmstruct.ItemData      // 移动后的物品数据
+	From   gameinventory.Location // 物品在源角色中的位置
+	To     gameinventory.Location // 物品在目标角色中的位置
+	Source []byte                 // 源角色重新编码后的数据
+	Target []byte                 // 目标角色重新编码后的数据
+	Diffs  []RoleDiffEntry        // 两个角色的合并差异，Section以"Source."或"Target."开头
+}
+
+// MoveItem : function to move an item between two decoded roles by GUID
+// 任何一步失败时两个角色都保持不变；Bak数据需要在成功后调用RoleBakEncoder.Encode重新编码
+func MoveItem(src, dst *RoleEncoder, guid int64, opts *ItemMoveOptions) (*ItemMoveResult, error) {
+	if opts == nil {
+		opts = new(ItemMoveOptions)
+	}
+
+	if len(opts.Places) == 0 {
+		return nil, fmt.Errorf("gameencoder: item move requires target places")
+	}
+
+	index := src.FindItem(guid)
+	if index < 0 {
+		return nil, fmt.Errorf("gameencoder: item GUID %d not found in source role", guid)
+	}
+	if i := dst.FindItem(guid); i >= 0 {
+		return nil, fmt.Errorf("gameencoder: item GUID %d already exists in target role at #%d", guid, i)
+	}
+
+	srcInv, err := gameinventory.Build(&src.RoleBaseData.RoleBaseInfo, src.ItemData, opts.Inventory)
+	if err != nil {
+		return nil, err
+	}
+	dstInv, err := gameinventory.Build(&dst.RoleBaseData.RoleBaseInfo, dst.ItemData, opts.Inventory)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(ItemMoveResult)
+	result.From, _ = srcInv.Locate(index)
+
+	// 在目标角色中查找空闲位置
+	item := src.ItemData[index]
+	loc, ok := dstInv.FindFree(&item, opts.Places...)
+	if !ok {
+		return nil, fmt.Errorf("gameencoder: no free cell for item GUID %d in target role", guid)
+	}
+	result.To = loc
+
+	item.Standard.Place = loc.Place
+	item.Standard.PosX = byte(loc.X)
+	item.Standard.PosY = byte(loc.Y)
+
+	if opts.RewriteOwner {
+		rewriteItemOwner(&item, dst)
+	}
+	result.Item = item
+
+	// 修改前保存副本，用于失败时恢复和生成差异
+	srcOld, dstOld := src.Clone(), dst.Clone()
+	restore := func() {
+		*src, *dst = *srcOld, *dstOld
+		src.Init()
+		dst.Init()
+	}
+
+	if _, _, err = src.RemoveItem(guid); err == nil {
+		_, err = dst.AddItem(item)
+	}
+	if err == nil {
+		result.Source, err = src.Encode()
+	}
+	if err == nil {
+		result.Target, err = dst.Encode()
+	}
+	if err != nil {
+		restore()
+		return nil, err
+	}
+
+	for _, d := range DiffRole(srcOld, src) {
+		d.Section = "Source." + d.Section
+		result.Diffs = append(result.Diffs, d)
+	}
+	for _, d := range DiffRole(dstOld, dst) {
+		d.Section = "Target." + d.Section
+		result.Diffs = append(result.Diffs, d)
+	}
+
+	return result, nil
+}
+
+// rewriteItemOwner : 将物品的锁魂归属人和装备所有者改为指定角色
+func rewriteItemOwner(item *gmstruct.ItemData, owner *RoleEncoder) {
+	if item.HasLockSoul {
+		item.LockSoul.Owner = owner.RoleBaseData.RoleName
+		if owner.RoleExtData.HasBase {
+			item.LockSoul.OwnerGUID = owner.RoleExtData.Base.RoleNameGUID
+		}
+	}
+
+	// 装备所有者只在已设置时修改
+	if item.HasExtend && !item.Extend.OwnerName.IsEmpty() {
+		item.Extend.OwnerName = owner.RoleBaseData.RoleName
+	}
+}
@@ -0,0 +1,73 @@
+package gameencoder
+
+import (
+	"testing"
+
+	"github.com/heartchord/jxonline/gameinventory"
+)
+
+// moveOptionsForTest : 只有一个6x10容器的布局
+func moveOptionsForTest() *ItemMoveOptions {
+	return &ItemMoveOptions{
+		Places:       []int32{3},
+		Inventory:    &gameinventory.Options{Containers: []gameinventory.Container{{Place: 3, Name: "bag", Width: 6, Height: 10}}},
+		RewriteOwner: true,
+	}
+}
+
+func TestMoveItem(t *testing.T) {
+	src := decodeForTest(t, generateForTest(t, 1))
+	dst := decodeForTest(t, generateForTest(t, 2))
+
+	// 生成的第3个物品包含标准数据和锁魂数据
+	const guid = 777777
+	item := &src.ItemData[2]
+	if !item.HasStandard || !item.HasLockSoul {
+		t.Fatal("generated item has no standard or lock soul data")
+	}
+	item.LockSoul.ItemGUID = guid
+	srcCount, dstCount := len(src.ItemData), len(dst.ItemData)
+
+	result, err := MoveItem(src, dst, guid, moveOptionsForTest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.FindItem(guid) >= 0 || len(src.ItemData) != srcCount-1 {
+		t.Fatal("item left in source role")
+	}
+	i := dst.FindItem(guid)
+	if i < 0 || len(dst.ItemData) != dstCount+1 {
+		t.Fatal("item not added to target role")
+	}
+	moved := &dst.ItemData[i]
+	if moved.Standard.Place != 3 || result.To.Place != 3 {
+		t.Fatalf("item placed in %d", moved.Standard.Place)
+	}
+	if moved.LockSoul.Owner != dst.RoleBaseData.RoleName {
+		t.Fatal("lock soul owner not rewritten")
+	}
+	if len(decodeForTest(t, result.Target).ItemData) != dstCount+1 {
+		t.Fatal("encoded target role has wrong item count")
+	}
+}
+
+func TestMoveItemRequiresPlaces(t *testing.T) {
+	src := decodeForTest(t, generateForTest(t, 1))
+	dst := decodeForTest(t, generateForTest(t, 2))
+	src.ItemData[2].LockSoul.ItemGUID = 777777
+
+	opts := moveOptionsForTest()
+	opts.Places = nil
+	if _, err := MoveItem(src, dst, 777777, opts); err == nil {
+		t.Fatal("move without places accepted")
+	}
+	if _, err := MoveItem(src, dst, 777777, nil); err == nil {
+		t.Fatal("move without options accepted")
+	}
+
+	opts = moveOptionsForTest()
+	opts.Inventory = nil
+	if _, err := MoveItem(src, dst, 777777, opts); err == nil {
+		t.Fatal("move without container layout accepted")
+	}
+}
@@ -0,0 +1,243 @@
+package gameencoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// RoleRawMigrateFunc : 原始数据迁移函数，用于数据布局发生变化的版本升级
+// 返回的数据必须是目标版本的布局，版本号和CRC32码由迁移器更新
+type RoleRawMigrateFunc func(data []byte, report *MigrationReport) ([]byte, error)
+
+// RoleMigrateFunc : 角色迁移函数，用于数据布局不变、只需修改字段的版本升级
+type RoleMigrateFunc func(en *RoleEncoder, report *MigrationReport) error
+
+// RoleMigration : 角色数据版本迁移步骤，Raw和Role至少设置一个，都设置时先执行Raw
+type RoleMigration struct {
+	From      uint32             // 源版本
+	To        uint32             // 目标版本
+	Name      string             // 迁移步骤名称
+	TransMark byte               // 迁移后写入DataTransMark的值，0表示不修改
+	Raw       RoleRawMigrateFunc // 原始数据迁移函数
+	Role      RoleMigrateFunc    // 角色迁移函数
+}
+
+// MigrationReport : 角色数据版本迁移结果
+type MigrationReport struct {
+	FromVersion uint32          // 迁移前版本
+	ToVersion   uint32          // 迁移后版本
+	Steps       []string        // 执行的迁移步骤
+	Defaulted   []RoleDiffEntry // 迁移步骤填充默认值的字段
+	Diffs       []RoleDiffEntry // 迁移前后基础数据的差异（只有迁移前的数据可以解码时才比较）和编码时清除的过期称号
+}
+
+// Default : 迁移步骤记录一个填充了默认值的字段
+func (r *MigrationReport) Default(section, field string, value interface{}) {
+	r.Defaulted = append(r.Defaulted, RoleDiffEntry{Section: section, Field: field, New: fmt.Sprintf("%v", value)})
+}
+
+// RoleMigrator : 角色数据版本迁移器，按注册的迁移步骤将旧版本角色数据依次升级到最新版本
+type RoleMigrator struct {
+	latest uint32
+	steps  map[uint32]*RoleMigration
+}
+
+// NewRoleMigrator : 创建一个RoleMigrator对象，latest为最新的角色数据版本
+func NewRoleMigrator(latest uint32) *RoleMigrator {
+	return &RoleMigrator{latest: latest, steps: make(map[uint32]*RoleMigration)}
+}
+
+// Latest : 获取最新的角色数据版本
+func (m *RoleMigrator) Latest() uint32 {
+	return m.latest
+}
+
+// Register : 注册一个迁移步骤，每个源版本只能有一个迁移步骤
+func (m *RoleMigrator) Register(step RoleMigration) error {
+	if step.To <= step.From || step.To > m.latest {
+		return fmt.Errorf("gameencoder: bad migration %q from version %d to %d", step.Name, step.From, step.To)
+	}
+	if step.Raw == nil && step.Role == nil {
+		return fmt.Errorf("gameencoder: migration %q has no migrate function", step.Name)
+	}
+	if old, ok := m.steps[step.From]; ok {
+		return fmt.Errorf("gameencoder: migration %q conflicts with %q from version %d", step.Name, old.Name, step.From)
+	}
+
+	m.steps[step.From] = &step
+	return nil
+}
+
+// Versions : 获取所有已注册迁移步骤的源版本，按版本排序
+func (m *RoleMigrator) Versions() []uint32 {
+	versions := make([]uint32, 0, len(m.steps))
+	for v := range m.steps {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+// chain : 获取从指定版本升级到最新版本的迁移步骤
+func (m *RoleMigrator) chain(version uint32) ([]*RoleMigration, error) {
+	if version > m.latest {
+		return nil, fmt.Errorf("gameencoder: role version %d is newer than %d", version, m.latest)
+	}
+
+	var steps []*RoleMigration
+	for version < m.latest {
+		step, ok := m.steps[version]
+		if !ok {
+			return nil, fmt.Errorf("gameencoder: no migration from role version %d", version)
+		}
+		steps = append(steps, step)
+		version = step.To
+	}
+	return steps, nil
+}
+
+// MigrateRole : function to migrate a decoded role to the latest version, only Role steps are allowed
+// 迁移在角色副本上进行，全部步骤成功后才替换原角色，失败时原角色保持不变
+func (m *RoleMigrator) MigrateRole(en *RoleEncoder) (*MigrationReport, error) {
+	steps, err := m.chain(en.RoleBaseData.Version)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range steps {
+		if step.Raw != nil {
+			return nil, fmt.Errorf("gameencoder: migration %q changes data layout, use MigrateData", step.Name)
+		}
+	}
+
+	report := &MigrationReport{FromVersion: en.RoleBaseData.Version, ToVersion: m.latest}
+
+	role := en.Clone()
+	for _, step := range steps {
+		if err := m.applyRole(role, step, report); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := role.UpdateLayout(); err != nil {
+		return nil, err
+	}
+	report.Diffs = diffStructFields("Base", "", reflect.ValueOf(en.RoleBaseData), reflect.ValueOf(role.RoleBaseData), nil)
+
+	*en = *role
+	en.Init()
+	return report, nil
+}
+
+// MigrateData : function to migrate original role binary data to the latest version and encode it
+func (m *RoleMigrator) MigrateData(data []byte) ([]byte, *MigrationReport, error) {
+	if len(data) < 8 {
+		return nil, nil, fmt.Errorf("gameencoder: role data too short")
+	}
+
+	// 版本号位于角色数据开头
+	version := binary.LittleEndian.Uint32(data)
+	steps, err := m.chain(version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &MigrationReport{FromVersion: version, ToVersion: m.latest}
+	var purged []RoleDiffEntry
+
+	// 迁移前的数据能以当前布局解码时才比较基础数据
+	en := NewRoleEncoder()
+	en.SetLogger(func(format string, a ...interface{}) (int, error) { return 0, nil })
+	decoded := en.Decode(data)
+	old := en.RoleBaseData
+
+	data = append([]byte(nil), data...)
+	for _, step := range steps {
+		if step.Raw != nil {
+			if data, err = step.Raw(data, report); err != nil {
+				return nil, nil, fmt.Errorf("gameencoder: migration %q : %v", step.Name, err)
+			}
+			if len(data) < 8 {
+				return nil, nil, fmt.Errorf("gameencoder: migration %q : role data too short", step.Name)
+			}
+			binary.LittleEndian.PutUint32(data, step.To)
+			binary.LittleEndian.PutUint32(data[len(data)-4:], CRC32(0, data[:len(data)-4]))
+		}
+
+		if !en.Decode(data) {
+			return nil, nil, fmt.Errorf("gameencoder: migration %q : cannot decode role data of version %d", step.Name, step.To)
+		}
+		if err := m.applyRole(en, step, report); err != nil {
+			return nil, nil, err
+		}
+		if data, err = en.Encode(); err != nil {
+			return nil, nil, err
+		}
+		purged = append(purged, en.PurgedTitleDiffs()...)
+	}
+
+	if !en.Decode(data) {
+		return nil, nil, fmt.Errorf("gameencoder: cannot decode migrated role data")
+	}
+	if decoded {
+		report.Diffs = diffStructFields("Base", "", reflect.ValueOf(old), reflect.ValueOf(en.RoleBaseData), nil)
+	}
+	report.Diffs = append(report.Diffs, purged...)
+	return data, report, nil
+}
+
+// MigrateBak : function to migrate original role bak data to the latest version and encode it
+func (m *RoleMigrator) MigrateBak(data []byte) ([]byte, *MigrationReport, error) {
+	bak := NewRoleBakEncoder()
+	bak.SetLogger(func(format string, a ...interface{}) (int, error) { return 0, nil })
+	if !bak.decodeBakHeader(data) {
+		return nil, nil, fmt.Errorf("gameencoder: bad role bak header")
+	}
+
+	roleData, report, err := m.MigrateData(bak.BakData.RoleData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !bak.RoleEncoder.Decode(roleData) {
+		return nil, nil, fmt.Errorf("gameencoder: cannot decode migrated role data")
+	}
+	data, err = bak.Encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, report, nil
+}
+
+// applyRole : 执行迁移步骤的角色迁移函数，并更新版本号和数据转换标记
+func (m *RoleMigrator) applyRole(en *RoleEncoder, step *RoleMigration, report *MigrationReport) error {
+	if step.Role != nil {
+		if err := step.Role(en, report); err != nil {
+			return fmt.Errorf("gameencoder: migration %q : %v", step.Name, err)
+		}
+	}
+
+	en.RoleBaseData.Version = step.To
+	if step.TransMark != 0 {
+		en.RoleBaseData.DataTransMark = step.TransMark
+	}
+	report.Steps = append(report.Steps, fmt.Sprintf("%d -> %d %s", step.From, step.To, step.Name))
+	return nil
+}
+
+// PrintMigrationReport : function to print result of role data version migration
+func PrintMigrationReport(report *MigrationReport) {
+	fmt.Println("=================================[MIGRATION]==================================")
+	fmt.Printf("Version = %d -> %d, Steps = %d, Defaulted = %d\n", report.FromVersion, report.ToVersion, len(report.Steps), len(report.Defaulted))
+
+	for _, step := range report.Steps {
+		fmt.Println(step)
+	}
+	for _, d := range report.Defaulted {
+		fmt.Println(d.String())
+	}
+	for _, d := range report.Diffs {
+		fmt.Println(d.String())
+	}
+}
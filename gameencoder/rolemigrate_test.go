@@ -0,0 +1,114 @@
+package gameencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+// migratorForTest : 版本1到3的迁移器，1 -> 2修改角色等级，2 -> 3为原始数据迁移
+func migratorForTest(t *testing.T, raw RoleRawMigrateFunc) *RoleMigrator {
+	m := NewRoleMigrator(3)
+	steps := []RoleMigration{
+		{From: 1, To: 2, Name: "level", Role: func(en *RoleEncoder, report *MigrationReport) error {
+			en.RoleBaseData.RoleLevel = 10
+			report.Default("Base", "RoleLevel", 10)
+			return nil
+		}},
+		{From: 2, To: 3, Name: "raw", TransMark: 7, Raw: raw},
+	}
+	for _, step := range steps {
+		if err := m.Register(step); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+// roleOfVersion : 生成指定版本的角色数据
+func roleOfVersion(t *testing.T, version uint32) []byte {
+	en := decodeForTest(t, generateForTest(t, 3))
+	en.RoleBaseData.Version = version
+	data, err := en.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestMigrateDataChain(t *testing.T) {
+	rawCalled := false
+	m := migratorForTest(t, func(data []byte, report *MigrationReport) ([]byte, error) {
+		rawCalled = true
+		return data, nil
+	})
+
+	out, report, err := m.MigrateData(roleOfVersion(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rawCalled {
+		t.Fatal("raw step not called")
+	}
+	if report.FromVersion != 1 || report.ToVersion != 3 || len(report.Steps) != 2 || len(report.Defaulted) != 1 {
+		t.Fatalf("bad report : %+v", report)
+	}
+
+	en := decodeForTest(t, out)
+	base := &en.RoleBaseData
+	if base.Version != 3 || base.RoleLevel != 10 || base.DataTransMark != 7 {
+		t.Fatalf("bad migrated role : version = %d, level = %d, mark = %d", base.Version, base.RoleLevel, base.DataTransMark)
+	}
+}
+
+func TestMigrateVersionMismatch(t *testing.T) {
+	m := migratorForTest(t, func(data []byte, report *MigrationReport) ([]byte, error) { return data, nil })
+
+	if _, _, err := m.MigrateData(roleOfVersion(t, 4)); err == nil {
+		t.Fatal("newer version accepted")
+	}
+	if _, _, err := m.MigrateData(roleOfVersion(t, 0)); err == nil {
+		t.Fatal("version without migration accepted")
+	}
+
+	// 原始数据迁移步骤只能用于MigrateData
+	en := decodeForTest(t, roleOfVersion(t, 1))
+	if _, err := m.MigrateRole(en); err == nil {
+		t.Fatal("raw step accepted by MigrateRole")
+	}
+	if en.RoleBaseData.Version != 1 {
+		t.Fatalf("role modified : version = %d", en.RoleBaseData.Version)
+	}
+}
+
+func TestMigrateRoleRollback(t *testing.T) {
+	m := NewRoleMigrator(2)
+	m.Register(RoleMigration{From: 0, To: 1, Name: "level", Role: func(en *RoleEncoder, report *MigrationReport) error {
+		en.RoleBaseData.RoleLevel++
+		return nil
+	}})
+	m.Register(RoleMigration{From: 1, To: 2, Name: "fail", Role: func(en *RoleEncoder, report *MigrationReport) error {
+		return fmt.Errorf("broken")
+	}})
+
+	en := decodeForTest(t, roleOfVersion(t, 0))
+	old := en.RoleBaseData
+	if _, err := m.MigrateRole(en); err == nil {
+		t.Fatal("failed migration returned no error")
+	}
+	if en.RoleBaseData != old {
+		t.Fatal("role modified by failed migration")
+	}
+}
+
+func TestMigrateBakBadHeader(t *testing.T) {
+	m := NewRoleMigrator(1)
+
+	// 角色名长度溢出时不能崩溃
+	data := binary.LittleEndian.AppendUint32(nil, 0xFFFFFFFF)
+	data = append(data, bytes.Repeat([]byte{1}, 16)...)
+	if _, _, err := m.MigrateBak(data); err == nil {
+		t.Fatal("bad bak header accepted")
+	}
+}
@@ -0,0 +1,249 @@
+package gameencoder
+
+import (
+	"fmt"
+	"sort"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// SkillKind : 技能类别
+type SkillKind int
+
+// 技能类别
+const (
+	FightSkill SkillKind = iota // 战斗技能
+	LifeSkill                   // 生活技能
+)
+
+// String : 技能类别名称
+func (k SkillKind) String() string {
+	if k == LifeSkill {
+		return "LSkill"
+	}
+	return "FSkill"
+}
+
+// skillList : 获取指定类别的技能列表
+func (en *RoleEncoder) skillList(kind SkillKind) *[]gmstruct.SkillData {
+	if kind == LifeSkill {
+		return &en.LSkillData
+	}
+	return &en.FSkillData
+}
+
+// itemGUID : 获取物品GUID，锁魂数据优先，没有GUID时返回false
+func itemGUID(item *gmstruct.ItemData) (int64, bool) {
+	if item.HasLockSoul && item.LockSoul.ItemGUID != 0 {
+		return item.LockSoul.ItemGUID, true
+	}
+	if item.HasBill && item.Bill.ItemGUID != 0 {
+		return item.Bill.ItemGUID, true
+	}
+	return 0, false
+}
+
+// Clone : function to make a deep copy of decoded role
+func (en *RoleEncoder) Clone() *RoleEncoder {
+	c := *en
+	c.FSkillData = append([]gmstruct.SkillData(nil), en.FSkillData...)
+	c.LSkillData = append([]gmstruct.SkillData(nil), en.LSkillData...)
+	c.TaskData = append([]gmstruct.TaskData(nil), en.TaskData...)
+	c.ItemData = append([]gmstruct.ItemData(nil), en.ItemData...)
+	c.SkillState = append([]gmstruct.SkillState(nil), en.SkillState...)
+	c.SkillCD = append([]gmstruct.SkillCD(nil), en.SkillCD...)
+	c.FeatureInfo = append([]gmstruct.FeatureInfo(nil), en.FeatureInfo...)
+	c.PlayerEvent = append([]gmstruct.PlayerEvent(nil), en.PlayerEvent...)
+	c.PlayerTitle = append([]gmstruct.RoleTitle(nil), en.PlayerTitle...)
+	c.MaxSkillLevel = append([]gmstruct.MaxSkillLevelInfo(nil), en.MaxSkillLevel...)
+	c.CustomStructHeader = append([]gmstruct.CustomDataHeader(nil), en.CustomStructHeader...)
+	c.CustomStructData = nil
+	for _, data := range en.CustomStructData {
+		c.CustomStructData = append(c.CustomStructData, append([]byte(nil), data...))
+	}
+	c.stateOrder = append([]byte(nil), en.stateOrder...)
+	c.extDataHeads = append([]gmstruct.DataHead(nil), en.extDataHeads...)
+	c.extPadding = append([]byte(nil), en.extPadding...)
+	c.tailPadding = append([]byte(nil), en.tailPadding...)
+
+	// 扩展数据解析函数绑定了原对象，需要重新初始化
+	c.Init()
+	return &c
+}
+
+// SetTaskValue : function to set value of a task variable, adds it if it does not exist
+// 新增的任务变量按ID顺序插入
+func (en *RoleEncoder) SetTaskValue(id, value int32) error {
+	for i := range en.TaskData {
+		if en.TaskData[i].TaskID == id {
+			en.TaskData[i].TaskValue = value
+			return nil
+		}
+	}
+
+	i := sort.Search(len(en.TaskData), func(i int) bool {
+		return en.TaskData[i].TaskID >= id
+	})
+	// 使用新的切片插入，失败时恢复原切片
+	old := en.TaskData
+	tasks := make([]gmstruct.TaskData, 0, len(old)+1)
+	tasks = append(tasks, old[:i]...)
+	tasks = append(tasks, gmstruct.TaskData{TaskID: id, TaskValue: value})
+	en.TaskData = append(tasks, old[i:]...)
+	if err := en.UpdateLayout(); err != nil {
+		en.TaskData = old
+		return err
+	}
+	return nil
+}
+
+// RemoveTask : function to remove a task variable, returns false if it does not exist
+func (en *RoleEncoder) RemoveTask(id int32) (bool, error) {
+	for i := range en.TaskData {
+		if en.TaskData[i].TaskID == id {
+			// 使用新的切片移除，失败时恢复原切片
+			old := en.TaskData
+			en.TaskData = append(append([]gmstruct.TaskData(nil), old[:i]...), old[i+1:]...)
+			if err := en.UpdateLayout(); err != nil {
+				en.TaskData = old
+				return false, err
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetSkill : function to set level of a skill, adds it if it does not exist
+// 已存在的技能保留技能经验
+func (en *RoleEncoder) SetSkill(kind SkillKind, id, level int16) error {
+	if id <= 0 || level < 0 {
+		return fmt.Errorf("gameencoder: bad %s skill %d level %d", kind, id, level)
+	}
+
+	skills := en.skillList(kind)
+	for i := range *skills {
+		if (*skills)[i].SkillID == id {
+			(*skills)[i].SkillLv = level
+			return nil
+		}
+	}
+
+	old := *skills
+	*skills = append(append([]gmstruct.SkillData(nil), old...), gmstruct.SkillData{SkillID: id, SkillLv: level})
+	if err := en.UpdateLayout(); err != nil {
+		*skills = old
+		return err
+	}
+	return nil
+}
+
+// RemoveSkill : function to remove a skill, returns false if it does not exist
+func (en *RoleEncoder) RemoveSkill(kind SkillKind, id int16) (bool, error) {
+	skills := en.skillList(kind)
+	for i := range *skills {
+		if (*skills)[i].SkillID == id {
+			old := *skills
+			*skills = append(append([]gmstruct.SkillData(nil), old[:i]...), old[i+1:]...)
+			if err := en.UpdateLayout(); err != nil {
+				*skills = old
+				return false, err
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddItem : function to add an item, returns index of the new item
+// 物品必须包含标准数据，有GUID时不能与已有物品重复
+func (en *RoleEncoder) AddItem(item gmstruct.ItemData) (int, error) {
+	if !item.HasStandard {
+		return -1, fmt.Errorf("gameencoder: item has no standard data")
+	}
+
+	if guid, ok := itemGUID(&item); ok {
+		if index := en.FindItem(guid); index >= 0 {
+			return -1, fmt.Errorf("gameencoder: item GUID %d already exists at #%d", guid, index)
+		}
+	}
+
+	en.ItemData = append(en.ItemData, item)
+	if err := en.UpdateLayout(); err != nil {
+		en.ItemData = en.ItemData[:len(en.ItemData)-1]
+		return -1, err
+	}
+	return len(en.ItemData) - 1, nil
+}
+
+// FindItem : function to find index of an item by GUID, returns -1 if not found
+func (en *RoleEncoder) FindItem(guid int64) int {
+	for i := range en.ItemData {
+		if g, ok := itemGUID(&en.ItemData[i]); ok && g == guid {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveItem : function to remove an item by GUID, returns the removed item
+func (en *RoleEncoder) RemoveItem(guid int64) (gmstruct.ItemData, bool, error) {
+	i := en.FindItem(guid)
+	if i < 0 {
+		return gmstruct.ItemData{}, false, nil
+	}
+
+	old := en.ItemData
+	en.ItemData = append(append([]gmstruct.ItemData(nil), old[:i]...), old[i+1:]...)
+	if err := en.UpdateLayout(); err != nil {
+		en.ItemData = old
+		return gmstruct.ItemData{}, false, err
+	}
+	return old[i], true, nil
+}
+
+// SetMoney : function to set money in bag and box
+func (en *RoleEncoder) SetMoney(bagMoney, boxMoney int32) error {
+	if bagMoney < 0 || boxMoney < 0 {
+		return fmt.Errorf("gameencoder: negative money bag %d box %d", bagMoney, boxMoney)
+	}
+
+	en.RoleBaseData.BagMoney = bagMoney
+	en.RoleBaseData.BoxMoney = boxMoney
+	return nil
+}
+
+// AddTitle : function to add a title, replaces the existing title with the same ID
+// 新称号为激活状态时取消其他称号的激活状态
+func (en *RoleEncoder) AddTitle(title gmstruct.RoleTitle) error {
+	if title.TitleTime.Type < 0 || title.TitleTime.Type >= gmstruct.RoleTitleTypeCount {
+		return fmt.Errorf("gameencoder: bad title %d time type %d", title.TitleID, title.TitleTime.Type)
+	}
+
+	old := en.PlayerTitle
+	titles := append([]gmstruct.RoleTitle(nil), old...)
+	if title.IsActiveTitleID != 0 {
+		for i := range titles {
+			titles[i].IsActiveTitleID = 0
+		}
+	}
+
+	replaced := false
+	for i := range titles {
+		if titles[i].TitleID == title.TitleID {
+			titles[i] = title
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		titles = append(titles, title)
+	}
+
+	en.PlayerTitle = titles
+	if err := en.UpdateLayout(); err != nil {
+		en.PlayerTitle = old
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package gameencoder
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// fillStates : 添加技能状态使状态总数达到编码上限，之后再添加任何状态数据都会使UpdateLayout失败
+func fillStates(t *testing.T, en *RoleEncoder) {
+	_, count, err := en.encodeRoleStateList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int(count); i < math.MaxInt16; i++ {
+		en.SkillState = append(en.SkillState, gmstruct.SkillState{})
+	}
+	if err := en.UpdateLayout(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddTitleActivates(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	count := len(en.PlayerTitle)
+
+	title := gmstruct.RoleTitle{TitleID: 900001, IsActiveTitleID: 1}
+	if err := en.AddTitle(title); err != nil {
+		t.Fatal(err)
+	}
+	if len(en.PlayerTitle) != count+1 {
+		t.Fatalf("title not added : %d -> %d", count, len(en.PlayerTitle))
+	}
+	for i := range en.PlayerTitle {
+		if active := en.PlayerTitle[i].IsActiveTitleID != 0; active != (en.PlayerTitle[i].TitleID == title.TitleID) {
+			t.Fatalf("title %d active = %v", en.PlayerTitle[i].TitleID, active)
+		}
+	}
+}
+
+func TestAddTitleRollback(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	for i := range en.PlayerTitle {
+		en.PlayerTitle[i].IsActiveTitleID = 1
+	}
+	fillStates(t, en)
+	old := append([]gmstruct.RoleTitle(nil), en.PlayerTitle...)
+
+	if err := en.AddTitle(gmstruct.RoleTitle{TitleID: 900001, IsActiveTitleID: 1}); err == nil {
+		t.Fatal("too many states accepted")
+	}
+	if !reflect.DeepEqual(en.PlayerTitle, old) {
+		t.Fatal("titles modified by failed AddTitle")
+	}
+}
+
+func TestSetTaskValueKeepsOrder(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	if err := en.SetTaskValue(1<<20, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := en.SetTaskValue(-1, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// 任务变量按ID顺序插入，编码后重新解码结果一致
+	for i := 1; i < len(en.TaskData); i++ {
+		if en.TaskData[i-1].TaskID >= en.TaskData[i].TaskID {
+			t.Fatalf("task %d not in order", en.TaskData[i].TaskID)
+		}
+	}
+	out, err := en.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := DiffRole(en, decodeForTest(t, out)); len(diffs) != 0 {
+		t.Fatalf("re-decoded role differs : %s", diffs[0])
+	}
+}
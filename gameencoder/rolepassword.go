@@ -0,0 +1,67 @@
+package gameencoder
+
+import (
+	"reflect"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// ResetBoxPassword : function to clear storage box password, returns modified fields
+func (en *RoleEncoder) ResetBoxPassword() []RoleDiffEntry {
+	old := en.RoleBaseData
+
+	en.RoleBaseData.BoxPassword = 0
+	en.RoleBaseData.BoxPasswordParam = 0
+
+	return diffStructFields("Base", "", reflect.ValueOf(old), reflect.ValueOf(en.RoleBaseData), nil)
+}
+
+// ResetBindPassword : function to clear bind and lock soul password, returns modified fields
+// 同时清除密码错误次数和解除密码日期
+func (en *RoleEncoder) ResetBindPassword() []RoleDiffEntry {
+	ext := &en.RoleExtData
+	if !ext.HasBase {
+		return nil
+	}
+	old := ext.Base
+
+	ext.Base.Password = 0
+	ext.Base.HavePassword = 0
+	ext.Base.PasswordTimeOrTimes = 0
+	ext.Base.PasswordExpiredTime = 0
+
+	return diffStructFields("Ext.Base", "", reflect.ValueOf(old), reflect.ValueOf(ext.Base), nil)
+}
+
+// ResetLingLongLock : function to clear LingLongLock password and unlock role, returns modified fields
+// 保留网卡和硬盘哈希，玩家重新设置玲珑锁时会被覆盖
+func (en *RoleEncoder) ResetLingLongLock() []RoleDiffEntry {
+	ext := &en.RoleExtData
+	if !ext.HasLingLongLock {
+		return nil
+	}
+	old := ext.LingLongLock
+
+	ext.LingLongLock.Password = 0
+	ext.LingLongLock.Timeout = 0
+	ext.LingLongLock.Locked = 0
+
+	return diffStructFields("Ext.LingLongLock", "", reflect.ValueOf(old), reflect.ValueOf(ext.LingLongLock), nil)
+}
+
+// ResetAllPasswords : function to clear storage box password, bind password and LingLongLock
+func (en *RoleEncoder) ResetAllPasswords() []RoleDiffEntry {
+	var report []RoleDiffEntry
+
+	report = append(report, en.ResetBoxPassword()...)
+	report = append(report, en.ResetBindPassword()...)
+	report = append(report, en.ResetLingLongLock()...)
+
+	return report
+}
+
+// clearRolePasswords : 清除所有密码和玲珑锁数据，用于从模板创建新角色
+func clearRolePasswords(role *RoleEncoder) {
+	role.ResetAllPasswords()
+	role.RoleExtData.LingLongLock = gmstruct.RoleExtDataOfLingLongLock{}
+}
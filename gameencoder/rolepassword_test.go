@@ -0,0 +1,72 @@
+package gameencoder
+
+import (
+	"testing"
+)
+
+func TestResetAllPasswords(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	base := &en.RoleBaseData
+	base.BoxPassword, base.BoxPasswordParam = 1234, 5
+	en.RoleExtData.Base.Password, en.RoleExtData.Base.HavePassword = 4321, 1
+	en.RoleExtData.LingLongLock.Password, en.RoleExtData.LingLongLock.Locked = 99, 1
+	hash := en.RoleExtData.LingLongLock
+
+	report := en.ResetAllPasswords()
+	sections := make(map[string]bool)
+	for _, d := range report {
+		sections[d.Section] = true
+	}
+	if !sections["Base"] || !sections["Ext.Base"] || !sections["Ext.LingLongLock"] {
+		t.Fatalf("bad report : %v", report)
+	}
+
+	out, err := en.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := decodeForTest(t, out)
+	if check.RoleBaseData.BoxPassword != 0 || check.RoleBaseData.BoxPasswordParam != 0 {
+		t.Fatal("box password not cleared")
+	}
+	if ext := &check.RoleExtData.Base; ext.Password != 0 || ext.HavePassword != 0 || ext.PasswordTimeOrTimes != 0 || ext.PasswordExpiredTime != 0 {
+		t.Fatalf("bind password not cleared : %+v", ext)
+	}
+
+	// 玲珑锁只清除密码和锁定状态，保留其他数据
+	lock := check.RoleExtData.LingLongLock
+	if lock.Password != 0 || lock.Timeout != 0 || lock.Locked != 0 {
+		t.Fatalf("LingLongLock not cleared : %+v", lock)
+	}
+	hash.Password, hash.Timeout, hash.Locked = 0, 0, 0
+	if lock != hash {
+		t.Fatal("LingLongLock hashes modified")
+	}
+
+	// 已清除时不再报告修改
+	if report := en.ResetAllPasswords(); len(report) != 0 {
+		t.Fatalf("reset twice reported %v", report)
+	}
+}
+
+func TestResetPasswordsWithoutExtData(t *testing.T) {
+	opts := DefaultRoleGenOptions()
+	opts.Seed = 1
+	opts.ExtData = false
+	data, err := NewRoleGenerator(opts).RoleData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	en := decodeForTest(t, data)
+
+	// 没有扩展数据时不能加入扩展数据块
+	if report := en.ResetBindPassword(); report != nil {
+		t.Fatalf("bind password reset without ext data : %v", report)
+	}
+	if report := en.ResetLingLongLock(); report != nil {
+		t.Fatalf("LingLongLock reset without ext data : %v", report)
+	}
+	if en.RoleExtData.HasBase || en.RoleExtData.HasLingLongLock {
+		t.Fatal("ext data added")
+	}
+}
@@ -0,0 +1,42 @@
+package gameencoder
+
+import (
+	"reflect"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+)
+
+// RelocateOptions : 角色位置修复选项
+type RelocateOptions struct {
+	Location      gamecatalog.SafeLocation // 目标安全位置
+	SetRevival    bool                     // 是否同时修改重生点
+	ClearExchange bool                     // 是否清除跨服标记（IsExchanged和IsExchangeServer）
+}
+
+// Relocate : function to move a stuck role to a safe location
+// 设置了地图配置查询服务时先检查目标位置是否有效，返回修改的字段
+func (en *RoleEncoder) Relocate(opts *RelocateOptions) ([]RoleDiffEntry, error) {
+	if err := opts.Location.Validate(en.mapCatalog); err != nil {
+		return nil, err
+	}
+
+	base := &en.RoleBaseData
+	old := *base
+
+	base.SubWorldID = int32(opts.Location.MapID)
+	base.SubWorldMpsX = opts.Location.X
+	base.SubWorldMpsY = opts.Location.Y
+	base.MapCopyIndex = 0 // 离开副本地图
+
+	if opts.SetRevival {
+		base.RevivalID = int32(opts.Location.RevivalID)
+		base.RevivalX = opts.Location.RevivalPoint
+	}
+
+	if opts.ClearExchange {
+		base.IsExchanged = 0
+		base.IsExchangeServer = 0
+	}
+
+	return diffStructFields("Base", "", reflect.ValueOf(old), reflect.ValueOf(*base), nil), nil
+}
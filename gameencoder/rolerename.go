@@ -0,0 +1,101 @@
+package gameencoder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// validateGBKName : 检查字符串是否可以保存为GBKName
+func validateGBKName(what, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("gameencoder: empty %s", what)
+	}
+	if strings.ContainsRune(name, 0) {
+		return fmt.Errorf("gameencoder: %s %q contains '\\0'", what, name)
+	}
+
+	n, ok := gmstruct.GBKLen(name)
+	if !ok {
+		return fmt.Errorf("gameencoder: %s %q contains characters not in GBK", what, name)
+	}
+	if n > gmstruct.GBKNameSize-1 {
+		return fmt.Errorf("gameencoder: %s %q is %d bytes in GBK, max %d", what, name, n, gmstruct.GBKNameSize-1)
+	}
+	return nil
+}
+
+// ValidateRoleName : function to check whether a role name can be stored as GBKName
+func ValidateRoleName(name string) error {
+	return validateGBKName("role name", name)
+}
+
+// ValidateAccount : function to check whether an account name can be stored as GBKName
+func ValidateAccount(account string) error {
+	return validateGBKName("account", account)
+}
+
+// Rename : function to rename role, updates role name and owner names of items belonging to the role
+// 只修改归属人为原角色名的物品，返回修改的字段
+func (en *RoleEncoder) Rename(name string) ([]RoleDiffEntry, error) {
+	if err := ValidateRoleName(name); err != nil {
+		return nil, err
+	}
+
+	var newName gmstruct.GBKName
+	newName.Set(name)
+
+	oldName := en.RoleBaseData.RoleName.Bytes()
+	if bytes.Equal(oldName, newName.Bytes()) {
+		return nil, nil
+	}
+
+	// 原角色名为空时不修改物品归属人
+	owned := func(owner gmstruct.GBKName) bool {
+		return len(oldName) > 0 && bytes.Equal(owner.Bytes(), oldName)
+	}
+
+	var report []RoleDiffEntry
+	touch := func(section, field string, target *gmstruct.GBKName) {
+		report = append(report, RoleDiffEntry{Section: section, Field: field, Old: target.String(), New: newName.String()})
+		*target = newName
+	}
+
+	touch("Base", "RoleName", &en.RoleBaseData.RoleName)
+
+	for i := range en.ItemData {
+		item := &en.ItemData[i]
+		key := itemDiffKey(*item, i)
+
+		if item.HasLockSoul && owned(item.LockSoul.Owner) {
+			touch("Item", fmt.Sprintf("Item[%s].LockSoul.Owner", key), &item.LockSoul.Owner)
+		}
+		if item.HasExtend && owned(item.Extend.OwnerName) {
+			touch("Item", fmt.Sprintf("Item[%s].Extend.OwnerName", key), &item.Extend.OwnerName)
+		}
+	}
+
+	return report, nil
+}
+
+// Rename : function to rename role in bak data, also updates role name in bak header
+func (en *RoleBakEncoder) Rename(name string) ([]RoleDiffEntry, error) {
+	report, err := en.RoleEncoder.Rename(name)
+	if err != nil || report == nil {
+		return report, err
+	}
+
+	oldName := gmstruct.DecodeGBK(en.BakData.RoleNameGBK)
+	oldLen := en.BakData.RoleNameLen
+
+	en.BakData.RoleNameGBK = en.RoleBaseData.RoleName.Bytes()
+	en.BakData.RoleNameLen = uint32(len(en.BakData.RoleNameGBK)) + 1 // 包含'\0'结束符
+
+	report = append(report,
+		RoleDiffEntry{Section: "BakHeader", Field: "RoleNameGBK", Old: oldName, New: en.BakData.RoleName()},
+		RoleDiffEntry{Section: "BakHeader", Field: "RoleNameLen", Old: fmt.Sprintf("%d", oldLen), New: fmt.Sprintf("%d", en.BakData.RoleNameLen)},
+	)
+	return report, nil
+}
@@ -0,0 +1,100 @@
+package gameencoder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenameUpdatesItemOwners(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	oldName := en.RoleBaseData.RoleName.String()
+
+	// 归属其他角色的物品不能被修改
+	other := -1
+	for i := range en.ItemData {
+		if en.ItemData[i].HasExtend {
+			en.ItemData[i].Extend.OwnerName.Set("Other")
+			other = i
+			break
+		}
+	}
+	if other < 0 {
+		t.Fatal("generated role has no extend item")
+	}
+
+	report, err := en.Rename("NewName")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) < 2 || report[0].Field != "RoleName" || report[0].Old != oldName {
+		t.Fatalf("bad report : %v", report)
+	}
+
+	out, err := en.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := decodeForTest(t, out)
+	if check.RoleBaseData.RoleName.String() != "NewName" {
+		t.Fatalf("role name = %q", check.RoleBaseData.RoleName.String())
+	}
+	for i := range check.ItemData {
+		item := &check.ItemData[i]
+		if item.HasLockSoul && item.LockSoul.Owner.String() != "NewName" {
+			t.Fatalf("item %d lock soul owner = %q", i, item.LockSoul.Owner.String())
+		}
+	}
+	if owner := check.ItemData[other].Extend.OwnerName.String(); owner != "Other" {
+		t.Fatalf("owner of other role's item changed to %q", owner)
+	}
+
+	// 同名时不修改
+	if report, err := en.Rename("NewName"); err != nil || report != nil {
+		t.Fatalf("rename to same name : %v, %v", report, err)
+	}
+}
+
+func TestRenameRejectsBadNames(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	old := en.RoleBaseData.RoleName
+
+	for _, name := range []string{"", "  ", "a\x00b", strings.Repeat("x", 32)} {
+		if _, err := en.Rename(name); err == nil {
+			t.Fatalf("bad name %q accepted", name)
+		}
+	}
+	if en.RoleBaseData.RoleName != old {
+		t.Fatal("role name modified by bad rename")
+	}
+}
+
+func TestRenameBak(t *testing.T) {
+	opts := DefaultRoleGenOptions()
+	opts.Seed = 1
+	data, err := NewRoleGenerator(opts).BakData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bak := NewRoleBakEncoder()
+	bak.SetLogger(quietLog)
+	if !bak.Decode(data) {
+		t.Fatal("decode bak failed")
+	}
+	if _, err := bak.Rename("BakName"); err != nil {
+		t.Fatal(err)
+	}
+	if bak.BakData.RoleName() != "BakName" || bak.BakData.RoleNameLen != uint32(len("BakName"))+1 {
+		t.Fatalf("bak header = %q, %d", bak.BakData.RoleName(), bak.BakData.RoleNameLen)
+	}
+
+	out, err := bak.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := NewRoleBakEncoder()
+	check.SetLogger(quietLog)
+	if !check.Decode(out) || check.BakData.RoleName() != "BakName" || check.RoleBaseData.RoleName.String() != "BakName" {
+		t.Fatal("renamed bak not decoded")
+	}
+}
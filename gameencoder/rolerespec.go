@@ -0,0 +1,85 @@
+package gameencoder
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+)
+
+// RespecCalculator : 潜能点计算器
+type RespecCalculator struct {
+	Points      gamecatalog.LevelPointTable // 各等级累计获得的潜能点
+	Bases       *gamecatalog.AttributeTable // 门派初始属性
+	ExtraPoints int                         // 任务、物品等其他途径获得的潜能点
+}
+
+// PropPointReport : 角色潜能点核对结果
+type PropPointReport struct {
+	Level     uint16                 // 角色等级
+	Base      gamecatalog.Attributes // 门派初始属性
+	Current   gamecatalog.Attributes // 当前属性
+	Allocated int                    // 已分配的潜能点（当前属性 - 初始属性）
+	Left      int                    // 剩余潜能点
+	Expected  int                    // 按等级应有的潜能点
+	Diff      int                    // 应有潜能点 - (已分配 + 剩余)，正数表示少了，负数表示多了
+}
+
+// String : 格式化潜能点核对结果
+func (r *PropPointReport) String() string {
+	return fmt.Sprintf("Level = %d, Allocated = %d, Left = %d, Expected = %d, Diff = %d",
+		r.Level, r.Allocated, r.Left, r.Expected, r.Diff)
+}
+
+// Check : function to compare allocated and left potential points with expected points of role level
+func (c *RespecCalculator) Check(en *RoleEncoder) (*PropPointReport, error) {
+	if c.Bases == nil {
+		return nil, fmt.Errorf("gameencoder: respec calculator has no base attribute table")
+	}
+
+	base := &en.RoleBaseData
+
+	attrs, ok := c.Bases.BaseAttributes(base.CurFaction)
+	if !ok {
+		return nil, fmt.Errorf("gameencoder: no base attributes of faction %s", base.CurFaction)
+	}
+
+	points, ok := c.Points.PointsAt(int(base.RoleLevel))
+	if !ok {
+		return nil, fmt.Errorf("gameencoder: no potential points of level %d", base.RoleLevel)
+	}
+
+	r := &PropPointReport{
+		Level:    base.RoleLevel,
+		Base:     attrs,
+		Current:  gamecatalog.AttributesOf(&base.RoleBaseInfo),
+		Left:     int(base.LeftPropPoint),
+		Expected: points + c.ExtraPoints,
+	}
+	r.Allocated = r.Current.Sum() - r.Base.Sum()
+	r.Diff = r.Expected - r.Allocated - r.Left
+	return r, nil
+}
+
+// Respec : function to reset attributes to faction base values and return allocated points to LeftPropPoint
+// 已分配的潜能点全部返还，与应有潜能点的差额不做修正，返回核对结果和修改的字段
+func (c *RespecCalculator) Respec(en *RoleEncoder) (*PropPointReport, []RoleDiffEntry, error) {
+	r, err := c.Check(en)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Allocated < 0 {
+		return r, nil, fmt.Errorf("gameencoder: attributes below faction base values : %s", r)
+	}
+
+	base := &en.RoleBaseData
+	old := *base
+
+	base.Strength = int32(r.Base.Strength)
+	base.Dexterity = int32(r.Base.Dexterity)
+	base.Vitality = int32(r.Base.Vitality)
+	base.Energy = int32(r.Base.Energy)
+	base.LeftPropPoint += int32(r.Allocated)
+
+	return r, diffStructFields("Base", "", reflect.ValueOf(old), reflect.ValueOf(*base), nil), nil
+}
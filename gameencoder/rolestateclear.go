@@ -0,0 +1,190 @@
+package gameencoder
+
+import (
+	"fmt"
+	"reflect"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// StateSelector : 技能状态和技能冷却的选择条件，各条件同时满足时选中，为空的条件不限制
+// StateTypes和NoClearOnDeath只适用于技能状态，设置了其中任一条件时不选择技能冷却
+type StateSelector struct {
+	SkillIDs       []int32 // 技能ID
+	StateTypes     []int32 // 技能状态类型
+	NoClearOnDeath bool    // 只选择死亡不清除的技能状态
+	All            bool    // 条件都为空时是否选择全部，未设置时ClearStates拒绝空的选择条件
+}
+
+// Empty : 选择条件是否为空
+func (s *StateSelector) Empty() bool {
+	return s == nil || (len(s.SkillIDs) == 0 && len(s.StateTypes) == 0 && !s.NoClearOnDeath)
+}
+
+// matchInt32 : 值是否在列表中，列表为空时不限制
+func matchInt32(list []int32, v int32) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchState : 技能状态是否满足选择条件
+func (s *StateSelector) MatchState(state *gmstruct.SkillState) bool {
+	if s.NoClearOnDeath && state.NoClearOnDeath == 0 {
+		return false
+	}
+	return matchInt32(s.SkillIDs, state.StateSkillID) && matchInt32(s.StateTypes, state.StateType)
+}
+
+// MatchCD : 技能冷却是否满足选择条件，空的冷却槽位不会被选中
+func (s *StateSelector) MatchCD(cd *gmstruct.SkillCDData) bool {
+	if len(s.StateTypes) > 0 || s.NoClearOnDeath || cd.SkillID == 0 {
+		return false
+	}
+	return matchInt32(s.SkillIDs, cd.SkillID)
+}
+
+// SkillCDRef : 技能冷却数据在角色数据中的位置
+type SkillCDRef struct {
+	Record int // SkillCD索引
+	Slot   int // SkillCD.Data索引
+}
+
+// SelectSkillStates : function to get indexes of all skill states matching selector
+func (en *RoleEncoder) SelectSkillStates(sel *StateSelector) []int {
+	var indexes []int
+	for i := range en.SkillState {
+		if sel.MatchState(&en.SkillState[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// SelectSkillCDs : function to get positions of all skill cooldowns matching selector
+func (en *RoleEncoder) SelectSkillCDs(sel *StateSelector) []SkillCDRef {
+	var refs []SkillCDRef
+	for i := range en.SkillCD {
+		for j := range en.SkillCD[i].Data {
+			if sel.MatchCD(&en.SkillCD[i].Data[j]) {
+				refs = append(refs, SkillCDRef{Record: i, Slot: j})
+			}
+		}
+	}
+	return refs
+}
+
+// StateClearResult : 清除技能状态和技能冷却的结果
+type StateClearResult struct {
+	DryRun  bool            // 是否只预览不修改
+	States  []int           // 选中的技能状态索引
+	CDs     []SkillCDRef    // 选中的技能冷却位置
+	Listing []string        // 选中的数据列表
+	Diffs   []RoleDiffEntry // 修改的字段
+	Expired []RoleDiffEntry // 重新编码时将被清除的过期称号
+}
+
+// ClearStates : function to remove skill states and clear skill cooldowns matching selector
+// 冷却槽位全部清空的SkillCD会被移除，dryRun为true时角色数据保持不变
+// 修改在角色副本上进行，成功后才替换原角色；修改后需要调用Encode写回，编码时会清除的过期称号列在结果的Expired中
+func (en *RoleEncoder) ClearStates(sel *StateSelector, dryRun bool) (*StateClearResult, error) {
+	if sel == nil || (sel.Empty() && !sel.All) {
+		return nil, fmt.Errorf("gameencoder: empty state selector, set All to clear all states and cooldowns")
+	}
+
+	result := &StateClearResult{DryRun: dryRun, States: en.SelectSkillStates(sel), CDs: en.SelectSkillCDs(sel)}
+
+	role := en.Clone()
+
+	// 技能状态整条移除
+	removed := make(map[int]bool)
+	for _, i := range result.States {
+		state := role.SkillState[i]
+		result.Listing = append(result.Listing, fmt.Sprintf("SkillState[%d] %+v", i, state))
+		result.Diffs = append(result.Diffs, RoleDiffEntry{Section: "SkillState", Field: fmt.Sprintf("SkillState[%d]", i), Old: fmt.Sprintf("%+v", state)})
+		removed[i] = true
+	}
+
+	states := make([]gmstruct.SkillState, 0, len(role.SkillState))
+	for i, state := range role.SkillState {
+		if !removed[i] {
+			states = append(states, state)
+		}
+	}
+	role.SkillState = states
+
+	// 技能冷却按槽位清除
+	olds := make(map[int]gmstruct.SkillCD)
+	for _, ref := range result.CDs {
+		if _, ok := olds[ref.Record]; !ok {
+			olds[ref.Record] = role.SkillCD[ref.Record]
+		}
+
+		data := &role.SkillCD[ref.Record].Data[ref.Slot]
+		result.Listing = append(result.Listing, fmt.Sprintf("SkillCD[%d].Data[%d] %+v", ref.Record, ref.Slot, *data))
+		*data = gmstruct.SkillCDData{}
+	}
+
+	cds := make([]gmstruct.SkillCD, 0, len(role.SkillCD))
+	for i, cd := range role.SkillCD {
+		old, ok := olds[i]
+		if !ok {
+			cds = append(cds, cd)
+			continue
+		}
+
+		field := fmt.Sprintf("SkillCD[%d]", i)
+		if skillCDEmpty(&cd) {
+			result.Diffs = append(result.Diffs, RoleDiffEntry{Section: "SkillCD", Field: field, Old: fmt.Sprintf("%+v", old)})
+			continue
+		}
+		result.Diffs = append(result.Diffs, diffStructFields("SkillCD", field+".", reflect.ValueOf(old), reflect.ValueOf(cd), nil)...)
+		cds = append(cds, cd)
+	}
+	role.SkillCD = cds
+
+	// 重新计算StateCount和各区块偏移
+	if err := role.UpdateLayout(); err != nil {
+		return nil, err
+	}
+	result.Expired = titleRemovalDiffs(role.ExpiredTitles())
+
+	if !dryRun {
+		*en = *role
+		en.Init()
+	}
+	return result, nil
+}
+
+// skillCDEmpty : SkillCD的所有冷却槽位是否为空
+func skillCDEmpty(cd *gmstruct.SkillCD) bool {
+	for i := range cd.Data {
+		if cd.Data[i].SkillID != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PrintStateClearResult : function to print result of clearing skill states and cooldowns
+func PrintStateClearResult(result *StateClearResult) {
+	fmt.Println("================================[STATE CLEAR]=================================")
+	fmt.Printf("DryRun = %v, States = %d, CDs = %d, Changes = %d, Expired = %d\n",
+		result.DryRun, len(result.States), len(result.CDs), len(result.Diffs), len(result.Expired))
+
+	for _, line := range result.Listing {
+		fmt.Println(line)
+	}
+	for _, d := range result.Diffs {
+		fmt.Println(d.String())
+	}
+	for _, d := range result.Expired {
+		fmt.Println(d.String())
+	}
+}
@@ -0,0 +1,64 @@
+package gameencoder
+
+import (
+	"testing"
+)
+
+func TestClearStatesRejectsEmptySelector(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+
+	if _, err := en.ClearStates(nil, true); err == nil {
+		t.Fatal("nil selector accepted")
+	}
+	if _, err := en.ClearStates(&StateSelector{}, true); err == nil {
+		t.Fatal("empty selector accepted")
+	}
+
+	result, err := en.ClearStates(&StateSelector{All: true}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.States) != len(en.SkillState) {
+		t.Fatalf("All selected %d of %d states", len(result.States), len(en.SkillState))
+	}
+}
+
+func TestClearStatesBySkill(t *testing.T) {
+	en := decodeForTest(t, generateForTest(t, 1))
+	if len(en.SkillState) == 0 {
+		t.Fatal("generated role has no skill state")
+	}
+	id := en.SkillState[0].StateSkillID
+	count := len(en.SkillState)
+	sel := &StateSelector{SkillIDs: []int32{id}}
+
+	// 预览不修改角色
+	preview, err := en.ClearStates(sel, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.States) == 0 || len(en.SkillState) != count {
+		t.Fatalf("bad preview : selected %d, states %d -> %d", len(preview.States), count, len(en.SkillState))
+	}
+
+	result, err := en.ClearStates(sel, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(en.SkillState) != count-len(result.States) {
+		t.Fatalf("states not removed : %d -> %d", count, len(en.SkillState))
+	}
+	for i := range en.SkillState {
+		if en.SkillState[i].StateSkillID == id {
+			t.Fatalf("state of skill %d left", id)
+		}
+	}
+
+	out, err := en.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check := decodeForTest(t, out); len(check.SkillState) != len(en.SkillState) {
+		t.Fatalf("decoded %d states, want %d", len(check.SkillState), len(en.SkillState))
+	}
+}
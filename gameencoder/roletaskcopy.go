@@ -0,0 +1,146 @@
+package gameencoder
+
+import (
+	"fmt"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// TaskRange : 任务变量ID范围，包含首尾
+type TaskRange struct {
+	From int32
+	To   int32
+}
+
+// TaskSelector : 任务变量选择条件，ID在IDs中或在任一范围内时选中，都为空时选择全部
+type TaskSelector struct {
+	IDs    []int32     // 任务变量ID
+	Ranges []TaskRange // 任务变量ID范围
+}
+
+// TaskSelectorOfGroup : 根据任务变量配置的分组创建选择条件，分组不存在或没有任务变量时返回错误
+func TaskSelectorOfGroup(table *gamecatalog.TaskTable, group string) (*TaskSelector, error) {
+	if table == nil {
+		return nil, fmt.Errorf("gameencoder: no task table for task group %q", group)
+	}
+
+	sel := &TaskSelector{}
+	for _, info := range table.TasksInGroup(group) {
+		sel.IDs = append(sel.IDs, int32(info.ID))
+	}
+	if sel.Empty() {
+		return nil, fmt.Errorf("gameencoder: task group %q is empty or unknown", group)
+	}
+	return sel, nil
+}
+
+// Empty : 选择条件是否为空
+func (s *TaskSelector) Empty() bool {
+	return s == nil || (len(s.IDs) == 0 && len(s.Ranges) == 0)
+}
+
+// Match : 任务变量ID是否满足选择条件
+func (s *TaskSelector) Match(id int32) bool {
+	if s.Empty() {
+		return true
+	}
+
+	for _, x := range s.IDs {
+		if x == id {
+			return true
+		}
+	}
+	for _, r := range s.Ranges {
+		if id >= r.From && id <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskCopyOptions : 复制任务变量选项
+type TaskCopyOptions struct {
+	RemoveMissing bool // 是否移除目标角色中选中但源角色中不存在的任务变量
+	DryRun        bool // 是否只预览不修改
+	All           bool // 选择条件为空时是否复制全部任务变量，未设置时空选择条件返回错误
+}
+
+// TaskCopyResult : 复制任务变量的结果
+type TaskCopyResult struct {
+	DryRun  bool            // 是否只预览不修改
+	Copied  []int32         // 复制的任务变量ID
+	Removed []int32         // 移除的任务变量ID
+	Diffs   []RoleDiffEntry // 目标角色被覆盖的任务变量和编码时清除的过期称号
+	Data    []byte          // 目标角色编码后的数据，预览时为空
+}
+
+// CopyTasks : function to copy selected task variables from src role to dst role and encode dst role
+// src可以是其他角色或同一角色的旧备份，目标角色的任务变量配置用于显示变量含义
+// 修改在目标角色的副本上进行，编码成功后才替换目标角色，失败时目标角色保持不变
+func CopyTasks(src, dst *RoleEncoder, sel *TaskSelector, opts TaskCopyOptions) (*TaskCopyResult, error) {
+	if src == dst {
+		return nil, fmt.Errorf("gameencoder: copy tasks to the same role")
+	}
+	if sel.Empty() && !opts.All {
+		return nil, fmt.Errorf("gameencoder: empty task selector, set All to copy all tasks")
+	}
+
+	result := &TaskCopyResult{DryRun: opts.DryRun}
+
+	role := dst.Clone()
+	old := append([]gmstruct.TaskData(nil), role.TaskData...)
+
+	exists := make(map[int32]bool)
+	for _, t := range src.TaskData {
+		if !sel.Match(t.TaskID) {
+			continue
+		}
+
+		exists[t.TaskID] = true
+		if err := role.SetTaskValue(t.TaskID, t.TaskValue); err != nil {
+			return nil, err
+		}
+		result.Copied = append(result.Copied, t.TaskID)
+	}
+
+	if opts.RemoveMissing {
+		for _, t := range old {
+			if !sel.Match(t.TaskID) || exists[t.TaskID] {
+				continue
+			}
+
+			if _, err := role.RemoveTask(t.TaskID); err != nil {
+				return nil, err
+			}
+			result.Removed = append(result.Removed, t.TaskID)
+		}
+	}
+
+	result.Diffs = diffTaskData(old, role.TaskData, dst.taskCatalog)
+	if opts.DryRun {
+		result.Diffs = append(result.Diffs, titleRemovalDiffs(role.ExpiredTitles())...)
+		return result, nil
+	}
+
+	data, err := role.Encode()
+	if err != nil {
+		return nil, err
+	}
+	result.Data = data
+	result.Diffs = append(result.Diffs, role.PurgedTitleDiffs()...)
+
+	*dst = *role
+	dst.Init()
+	return result, nil
+}
+
+// PrintTaskCopyResult : function to print result of copying task variables
+func PrintTaskCopyResult(result *TaskCopyResult) {
+	fmt.Println("=================================[TASK COPY]==================================")
+	fmt.Printf("DryRun = %v, Copied = %d, Removed = %d, Changes = %d\n", result.DryRun, len(result.Copied), len(result.Removed), len(result.Diffs))
+
+	for _, d := range result.Diffs {
+		fmt.Println(d.String())
+	}
+}
@@ -0,0 +1,119 @@
+package gameencoder
+
+import (
+	"testing"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+)
+
+// taskValueForTest : 获取角色中的任务变量值
+func taskValueForTest(en *RoleEncoder, id int32) (int32, bool) {
+	for _, t := range en.TaskData {
+		if t.TaskID == id {
+			return t.TaskValue, true
+		}
+	}
+	return 0, false
+}
+
+// taskRolesForTest : 源角色有任务变量50001，目标角色有任务变量50002
+func taskRolesForTest(t *testing.T) (src, dst *RoleEncoder) {
+	src = decodeForTest(t, generateForTest(t, 1))
+	dst = decodeForTest(t, generateForTest(t, 2))
+
+	if err := src.SetTaskValue(50001, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.SetTaskValue(50001, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.SetTaskValue(50002, 9); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.RemoveTask(50002); err != nil {
+		t.Fatal(err)
+	}
+	return src, dst
+}
+
+func TestCopyTasksRejectsEmptySelector(t *testing.T) {
+	src, dst := taskRolesForTest(t)
+
+	if _, err := CopyTasks(src, dst, nil, TaskCopyOptions{DryRun: true}); err == nil {
+		t.Fatal("nil selector accepted")
+	}
+	if _, err := CopyTasks(src, dst, &TaskSelector{}, TaskCopyOptions{DryRun: true}); err == nil {
+		t.Fatal("empty selector accepted")
+	}
+	if _, err := CopyTasks(src, src, &TaskSelector{IDs: []int32{50001}}, TaskCopyOptions{}); err == nil {
+		t.Fatal("copy to the same role accepted")
+	}
+
+	result, err := CopyTasks(src, dst, nil, TaskCopyOptions{DryRun: true, All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Copied) != len(src.TaskData) {
+		t.Fatalf("All copied %d of %d tasks", len(result.Copied), len(src.TaskData))
+	}
+}
+
+func TestCopyTasksRange(t *testing.T) {
+	src, dst := taskRolesForTest(t)
+	sel := &TaskSelector{Ranges: []TaskRange{{From: 50000, To: 50010}}}
+
+	// 预览不修改目标角色
+	preview, err := CopyTasks(src, dst, sel, TaskCopyOptions{RemoveMissing: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Data != nil {
+		t.Fatal("preview encoded role")
+	}
+	if v, _ := taskValueForTest(dst, 50001); v != 1 {
+		t.Fatalf("preview modified task 50001 : %d", v)
+	}
+
+	result, err := CopyTasks(src, dst, sel, TaskCopyOptions{RemoveMissing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Copied) != 1 || result.Copied[0] != 50001 || len(result.Removed) != 1 || result.Removed[0] != 50002 {
+		t.Fatalf("copied %v, removed %v", result.Copied, result.Removed)
+	}
+	if len(preview.Diffs) != len(result.Diffs) {
+		t.Fatalf("preview has %d changes, run has %d", len(preview.Diffs), len(result.Diffs))
+	}
+
+	check := decodeForTest(t, result.Data)
+	if v, ok := taskValueForTest(check, 50001); !ok || v != 7 {
+		t.Fatalf("task 50001 = %d, %v", v, ok)
+	}
+	if _, ok := taskValueForTest(check, 50002); ok {
+		t.Fatal("task 50002 not removed")
+	}
+	if len(check.TaskData) != len(dst.TaskData) {
+		t.Fatalf("decoded %d tasks, role has %d", len(check.TaskData), len(dst.TaskData))
+	}
+}
+
+func TestTaskSelectorOfGroup(t *testing.T) {
+	table := gamecatalog.NewTaskTable()
+	table.Add(&gamecatalog.TaskInfo{ID: 50001, Group: "story"})
+	table.Add(&gamecatalog.TaskInfo{ID: 50003, Group: "daily"})
+
+	sel, err := TaskSelectorOfGroup(table, "story")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sel.Match(50001) || sel.Match(50003) {
+		t.Fatalf("bad group selector : %+v", sel)
+	}
+
+	if _, err := TaskSelectorOfGroup(table, "unknown"); err == nil {
+		t.Fatal("unknown group accepted")
+	}
+	if _, err := TaskSelectorOfGroup(nil, "story"); err == nil {
+		t.Fatal("nil task table accepted")
+	}
+}
@@ -0,0 +1,209 @@
+package gameencoder
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// TitleRemaining : 获取称号的剩余时间，普通称号没有时间限制，返回false
+func TitleRemaining(title *gmstruct.RoleTitle) (time.Duration, bool) {
+	switch title.TitleTime.Type {
+	case gmstruct.RoleTitleTypeOfGameTime:
+		return time.Duration(title.TitleTime.Time) * time.Second / gmstruct.GameFPS, true
+	case gmstruct.RoleTitleTypeOfTrueTime:
+		return time.Duration(title.TitleTime.TrueTime) * time.Second, true
+	}
+	return 0, false
+}
+
+// TitleExpired : 称号是否已过期，普通称号永不过期
+func TitleExpired(title *gmstruct.RoleTitle) bool {
+	d, ok := TitleRemaining(title)
+	return ok && d <= 0
+}
+
+// FormatTitleRemaining : 将称号剩余时间格式化为"X天X小时X分X秒"
+func FormatTitleRemaining(title *gmstruct.RoleTitle) string {
+	d, ok := TitleRemaining(title)
+	if !ok {
+		return "永久"
+	}
+	if d <= 0 {
+		return "已过期"
+	}
+
+	secs := int64(d / time.Second)
+	days, secs := secs/86400, secs%86400
+	hours, secs := secs/3600, secs%3600
+	mins, secs := secs/60, secs%60
+
+	s := ""
+	if days > 0 {
+		s += fmt.Sprintf("%d天", days)
+	}
+	if hours > 0 {
+		s += fmt.Sprintf("%d小时", hours)
+	}
+	if mins > 0 {
+		s += fmt.Sprintf("%d分", mins)
+	}
+	if secs > 0 || s == "" {
+		s += fmt.Sprintf("%d秒", secs)
+	}
+	return s
+}
+
+// findTitle : 查找称号的索引，不存在时返回-1
+func (en *RoleEncoder) findTitle(id uint32) int {
+	for i := range en.PlayerTitle {
+		if en.PlayerTitle[i].TitleID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveTitle : function to remove a title, returns false if the title does not exist
+func (en *RoleEncoder) RemoveTitle(id uint32) (bool, error) {
+	i := en.findTitle(id)
+	if i < 0 {
+		return false, nil
+	}
+
+	old := en.PlayerTitle
+	en.PlayerTitle = append(append([]gmstruct.RoleTitle(nil), old[:i]...), old[i+1:]...)
+	if err := en.UpdateLayout(); err != nil {
+		en.PlayerTitle = old
+		return false, err
+	}
+	return true, nil
+}
+
+// ActivateTitle : function to make a title the active one, id 0 deactivates all titles
+func (en *RoleEncoder) ActivateTitle(id uint32) error {
+	i := -1
+	if id != 0 {
+		if i = en.findTitle(id); i < 0 {
+			return fmt.Errorf("gameencoder: title %d not found", id)
+		}
+		if TitleExpired(&en.PlayerTitle[i]) {
+			return fmt.Errorf("gameencoder: title %d expired", id)
+		}
+	}
+
+	for j := range en.PlayerTitle {
+		if j == i {
+			en.PlayerTitle[j].IsActiveTitleID = 1
+		} else {
+			en.PlayerTitle[j].IsActiveTitleID = 0
+		}
+	}
+	return nil
+}
+
+// ExtendTitle : function to extend remaining time of a timed title, expired titles start from zero
+func (en *RoleEncoder) ExtendTitle(id uint32, d time.Duration) error {
+	i := en.findTitle(id)
+	if i < 0 {
+		return fmt.Errorf("gameencoder: title %d not found", id)
+	}
+
+	t := &en.PlayerTitle[i].TitleTime
+	switch t.Type {
+	case gmstruct.RoleTitleTypeOfGameTime:
+		v, err := extendTitleTime(id, t.Time, int64(d*gmstruct.GameFPS/time.Second))
+		if err != nil {
+			return err
+		}
+		t.Time = v
+	case gmstruct.RoleTitleTypeOfTrueTime:
+		v, err := extendTitleTime(id, t.TrueTime, int64(d/time.Second))
+		if err != nil {
+			return err
+		}
+		t.TrueTime = v
+	default:
+		return fmt.Errorf("gameencoder: title %d has no time limit", id)
+	}
+	return nil
+}
+
+// extendTitleTime : 计算延长后的称号时间，检查溢出
+func extendTitleTime(id uint32, cur int32, add int64) (int32, error) {
+	if cur < 0 {
+		cur = 0
+	}
+
+	v := int64(cur) + add
+	if v < 0 || v > math.MaxInt32 {
+		return 0, fmt.Errorf("gameencoder: title %d time %d out of range", id, v)
+	}
+	return int32(v), nil
+}
+
+// ExpiredTitles : function to list all expired titles without removing them
+func (en *RoleEncoder) ExpiredTitles() []gmstruct.RoleTitle {
+	var expired []gmstruct.RoleTitle
+	for i := range en.PlayerTitle {
+		if TitleExpired(&en.PlayerTitle[i]) {
+			expired = append(expired, en.PlayerTitle[i])
+		}
+	}
+	return expired
+}
+
+// PurgeExpiredTitles : function to remove all expired titles, returns removed titles
+func (en *RoleEncoder) PurgeExpiredTitles() []gmstruct.RoleTitle {
+	var removed []gmstruct.RoleTitle
+
+	titles := make([]gmstruct.RoleTitle, 0, len(en.PlayerTitle))
+	for _, title := range en.PlayerTitle {
+		if TitleExpired(&title) {
+			removed = append(removed, title)
+			continue
+		}
+		titles = append(titles, title)
+	}
+	en.PlayerTitle = titles
+	return removed
+}
+
+// PurgedTitles : 获取上次编码时清除的过期称号
+func (en *RoleEncoder) PurgedTitles() []gmstruct.RoleTitle {
+	return en.purgedTitles
+}
+
+// PurgedTitleDiffs : 将上次编码时清除的过期称号格式化为差异项，用于加入修改报告
+func (en *RoleEncoder) PurgedTitleDiffs() []RoleDiffEntry {
+	return titleRemovalDiffs(en.purgedTitles)
+}
+
+// titleRemovalDiffs : 将移除的称号格式化为差异项
+func titleRemovalDiffs(titles []gmstruct.RoleTitle) []RoleDiffEntry {
+	var diffs []RoleDiffEntry
+	for _, title := range titles {
+		diffs = append(diffs, RoleDiffEntry{
+			Section: "PlayerTitle",
+			Field:   fmt.Sprintf("PlayerTitle[ID %d]", title.TitleID),
+			Old:     fmt.Sprintf("%+v", title),
+		})
+	}
+	return diffs
+}
+
+// PrintAllTitleData : function to print all titles with remaining time
+func (en RoleEncoder) PrintAllTitleData() {
+	fmt.Println("=================================[ROLE TITLE]=================================")
+	for i := range en.PlayerTitle {
+		title := &en.PlayerTitle[i]
+		active := ""
+		if title.IsActiveTitleID != 0 {
+			active = " [Active]"
+		}
+		fmt.Printf("Title = { ID : %-6d, Type : %d, Remaining : %s }%s\n",
+			title.TitleID, title.TitleTime.Type, FormatTitleRemaining(title), active)
+	}
+}
@@ -0,0 +1,167 @@
+package gameencoder
+
+import (
+	"fmt"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+)
+
+// TransferOptions : 跨服转移选项
+type TransferOptions struct {
+	Table     *gamecatalog.TransferTable // ID映射表，只在PrepareTransfer中使用，为空时不映射ID；转回源服务器时使用Table.Inverse()
+	Locations gamecatalog.SafeLocations  // 目标服务器的安全传送点，打包时将角色移动到映射后重生点地图上的传送点
+	Validator *RoleValidator             // 目标服务器的角色数据校验器，不能为空
+	Strict    bool                       // 校验发现问题时是否返回错误
+}
+
+// check : 检查跨服转移选项
+func (opts *TransferOptions) check() error {
+	if opts == nil {
+		return fmt.Errorf("gameencoder: no transfer options")
+	}
+	if opts.Validator == nil {
+		return fmt.Errorf("gameencoder: no role validator of target server")
+	}
+	return nil
+}
+
+// TransferResult : 跨服转移结果
+type TransferResult struct {
+	Role   *RoleBakEncoder // 转移后的角色
+	Data   []byte          // 转移后的Bak数据
+	Diffs  []RoleDiffEntry // 修改的数据
+	Issues []RoleIssue     // 校验发现的问题
+}
+
+// PrepareTransfer : function to package a role for moving to another server
+// 设置跨服标记，按映射表重新映射帮会、地图和GUID，再将角色移动到映射后重生点地图上的传送点
+// 传送点坐标属于目标服务器的地图，重生点地图上没有配置传送点时返回错误；原角色不会被修改
+func PrepareTransfer(role *RoleEncoder, opts *TransferOptions) (*TransferResult, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+
+	base := &role.RoleBaseData
+	if base.IsExchanged != 0 || base.IsExchangeServer != 0 {
+		return nil, fmt.Errorf("gameencoder: role %s is already in transfer", base.RoleName)
+	}
+
+	return transferRole(role, opts, func(en *RoleEncoder) error {
+		remapTransferIDs(en, opts.Table)
+
+		base := &en.RoleBaseData
+		loc, ok := opts.Locations.FindMap(int(base.RevivalID))
+		if !ok {
+			return fmt.Errorf("gameencoder: no safe location on revival map %d of role %s", base.RevivalID, base.RoleName)
+		}
+		if err := loc.Validate(opts.Validator.Maps); err != nil {
+			return err
+		}
+
+		base.SubWorldID = int32(loc.MapID)
+		base.SubWorldMpsX = loc.X
+		base.SubWorldMpsY = loc.Y
+		base.MapCopyIndex = 0 // 离开副本地图
+		base.IsExchanged = 1
+		base.IsExchangeServer = 1
+		return nil
+	})
+}
+
+// AcceptTransfer : function to accept a role packaged by PrepareTransfer on target server
+// 只清除跨服标记并校验，ID已在PrepareTransfer中映射，不再使用映射表；原角色不会被修改
+func AcceptTransfer(role *RoleEncoder, opts *TransferOptions) (*TransferResult, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	if role.RoleBaseData.IsExchanged == 0 && role.RoleBaseData.IsExchangeServer == 0 {
+		return nil, fmt.Errorf("gameencoder: role %s is not in transfer", role.RoleBaseData.RoleName)
+	}
+
+	return transferRole(role, opts, func(en *RoleEncoder) error {
+		en.RoleBaseData.IsExchanged = 0
+		en.RoleBaseData.IsExchangeServer = 0
+		return nil
+	})
+}
+
+// transferRole : 在角色副本上执行修改，编码为Bak数据后重新解码并校验
+func transferRole(role *RoleEncoder, opts *TransferOptions, edit func(en *RoleEncoder) error) (*TransferResult, error) {
+	bak := NewRoleBakEncoder()
+	bak.RoleEncoder = *role.Clone()
+	bak.RoleEncoder.Init()
+
+	if err := edit(&bak.RoleEncoder); err != nil {
+		return nil, err
+	}
+
+	data, err := bak.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TransferResult{Role: bak, Data: data, Diffs: DiffRole(role, &bak.RoleEncoder)}
+
+	// 校验编码结果可以被重新解码
+	check := NewRoleBakEncoder()
+	check.SetLogger(func(format string, a ...interface{}) (int, error) { return 0, nil })
+	if !check.Decode(data) || check.CRC32Cal != check.CRC32Read {
+		return nil, fmt.Errorf("gameencoder: transferred role %s cannot be decoded", role.RoleBaseData.RoleName)
+	}
+
+	result.Issues = opts.Validator.Validate(&check.RoleEncoder)
+	if opts.Strict && len(result.Issues) > 0 {
+		return result, fmt.Errorf("gameencoder: transferred role %s has %d issues, first : %s",
+			role.RoleBaseData.RoleName, len(result.Issues), result.Issues[0])
+	}
+	return result, nil
+}
+
+// remapTransferIDs : 按映射表重新映射帮会、地图和GUID，未配置的帮会退出帮会
+func remapTransferIDs(en *RoleEncoder, t *gamecatalog.TransferTable) {
+	if t == nil {
+		return
+	}
+
+	base := &en.RoleBaseData
+	if base.TongID != 0 {
+		tong, ok := t.Tong(base.TongID)
+		if !ok {
+			tong = 0
+		}
+		base.TongID = tong
+	}
+
+	base.RevivalID = int32(t.Map(int(base.RevivalID)))
+	base.SubWorldID = int32(t.Map(int(base.SubWorldID)))
+
+	ext := &en.RoleExtData
+	if ext.HasBase {
+		ext.Base.RoleNameGUID = t.GUID(ext.Base.RoleNameGUID)
+	}
+
+	for i := range en.ItemData {
+		item := &en.ItemData[i]
+		if item.HasLockSoul {
+			item.LockSoul.ItemGUID = t.GUID(item.LockSoul.ItemGUID)
+			item.LockSoul.OwnerGUID = t.GUID(item.LockSoul.OwnerGUID)
+		}
+		if item.HasBill {
+			item.Bill.ItemGUID = t.GUID(item.Bill.ItemGUID)
+		}
+	}
+}
+
+// PrintTransferResult : function to print result of cross-server transfer
+func PrintTransferResult(result *TransferResult) {
+	fmt.Println("==================================[TRANSFER]==================================")
+	fmt.Printf("Role = %s, Size = %d, Changes = %d, Issues = %d\n",
+		result.Role.RoleBaseData.RoleName, len(result.Data), len(result.Diffs), len(result.Issues))
+
+	for _, d := range result.Diffs {
+		fmt.Println(d.String())
+	}
+	for _, issue := range result.Issues {
+		fmt.Println(issue.String())
+	}
+}
@@ -0,0 +1,102 @@
+package gameencoder
+
+import (
+	"testing"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+)
+
+// transferOptionsForTest : 帮会1001映射为2001，地图301映射为401
+func transferOptionsForTest() *TransferOptions {
+	table := gamecatalog.NewTransferTable()
+	table.Tongs[1001] = 2001
+	table.Maps[301] = 401
+
+	return &TransferOptions{
+		Table:     table,
+		Locations: gamecatalog.SafeLocations{{Name: "target", MapID: 401, X: 1600, Y: 3200, RevivalID: 401}},
+		Validator: NewRoleValidator(),
+	}
+}
+
+// transferRoleForTest : 生成一个不在跨服状态的角色，位于副本地图301
+func transferRoleForTest(t *testing.T) *RoleEncoder {
+	en := decodeForTest(t, generateForTest(t, 1))
+	base := &en.RoleBaseData
+	base.IsExchanged = 0
+	base.IsExchangeServer = 0
+	base.TongID = 1001
+	base.RevivalID = 301
+	base.SubWorldID = 301
+	base.MapCopyIndex = 5
+	return en
+}
+
+func TestTransferRoundTrip(t *testing.T) {
+	role := transferRoleForTest(t)
+	opts := transferOptionsForTest()
+
+	prepared, err := PrepareTransfer(role, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := &prepared.Role.RoleBaseData
+	if base.TongID != 2001 || base.RevivalID != 401 || base.SubWorldID != 401 {
+		t.Fatalf("IDs not remapped : tong = %d, revival = %d, map = %d", base.TongID, base.RevivalID, base.SubWorldID)
+	}
+	if base.SubWorldMpsX != 1600 || base.SubWorldMpsY != 3200 || base.MapCopyIndex != 0 {
+		t.Fatalf("role not moved to safe location : (%d, %d) copy %d", base.SubWorldMpsX, base.SubWorldMpsY, base.MapCopyIndex)
+	}
+	if base.IsExchanged == 0 || base.IsExchangeServer == 0 {
+		t.Fatal("transfer flags not set")
+	}
+	if role.RoleBaseData.TongID != 1001 || role.RoleBaseData.IsExchanged != 0 {
+		t.Fatal("source role modified")
+	}
+
+	// 目标服务器使用同一组选项接收角色，ID不能被再次映射
+	bak := NewRoleBakEncoder()
+	bak.SetLogger(quietLog)
+	if !bak.Decode(prepared.Data) {
+		t.Fatal("decode prepared bak failed")
+	}
+	accepted, err := AcceptTransfer(&bak.RoleEncoder, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base = &accepted.Role.RoleBaseData
+	if base.TongID != 2001 || base.RevivalID != 401 || base.SubWorldID != 401 {
+		t.Fatalf("IDs changed on accept : tong = %d, revival = %d, map = %d", base.TongID, base.RevivalID, base.SubWorldID)
+	}
+	if base.IsExchanged != 0 || base.IsExchangeServer != 0 {
+		t.Fatal("transfer flags not cleared")
+	}
+	for _, d := range accepted.Diffs {
+		if d.Section != "Base" || (d.Field != "IsExchanged" && d.Field != "IsExchangeServer") {
+			t.Fatalf("unexpected change on accept : %s", d)
+		}
+	}
+}
+
+func TestTransferRejectsBadOptions(t *testing.T) {
+	role := transferRoleForTest(t)
+
+	if _, err := PrepareTransfer(role, nil); err == nil {
+		t.Fatal("nil options accepted")
+	}
+	if _, err := AcceptTransfer(role, nil); err == nil {
+		t.Fatal("nil options accepted")
+	}
+
+	opts := transferOptionsForTest()
+	opts.Validator = nil
+	if _, err := PrepareTransfer(role, opts); err == nil {
+		t.Fatal("options without validator accepted")
+	}
+
+	opts = transferOptionsForTest()
+	opts.Locations = nil
+	if _, err := PrepareTransfer(role, opts); err == nil {
+		t.Fatal("revival map without safe location accepted")
+	}
+}
@@ -0,0 +1,183 @@
+package gameencoder
+
+import (
+	"fmt"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+// RoleIssue : 角色数据校验发现的问题
+type RoleIssue struct {
+	Section string // 数据区块名称
+	Field   string // 数据名称
+	Message string // 问题说明
+}
+
+// String : 格式化校验问题
+func (i RoleIssue) String() string {
+	return fmt.Sprintf("[%s] %s : %s", i.Section, i.Field, i.Message)
+}
+
+// RoleValidator : 角色数据校验器
+type RoleValidator struct {
+	Skills      gamecatalog.SkillCatalog    // 技能配置，为空时不校验技能
+	SkillPoints gamecatalog.LevelPointTable // 各等级累计获得的技能点，为空时不校验剩余技能点
+	Maps        gamecatalog.MapCatalog      // 地图配置，为空时不校验重生点和登出地图
+}
+
+// NewRoleValidator : 创建一个RoleValidator对象
+func NewRoleValidator() *RoleValidator {
+	return new(RoleValidator)
+}
+
+// Validate : function to validate decoded role data
+func (v *RoleValidator) Validate(en *RoleEncoder) []RoleIssue {
+	var issues []RoleIssue
+
+	issues = append(issues, v.validateRoleEnums(en)...)
+	issues = append(issues, v.validateRoleSkills(en)...)
+	issues = append(issues, v.validateRoleLocation(en)...)
+
+	return issues
+}
+
+// PrintRoleIssues : function to print all role validation issues
+func PrintRoleIssues(issues []RoleIssue) {
+	fmt.Println("===============================[ROLE VALIDATION]==============================")
+	fmt.Printf("Total = %d\n", len(issues))
+
+	for _, issue := range issues {
+		fmt.Println(issue.String())
+	}
+}
+
+// validateRoleEnums : 检查枚举类型的角色基础数据是否为已定义的值
+func (v *RoleValidator) validateRoleEnums(en *RoleEncoder) []RoleIssue {
+	var issues []RoleIssue
+
+	base := &en.RoleBaseData
+	check := func(field string, valid bool, value fmt.Stringer) {
+		if !valid {
+			issues = append(issues, RoleIssue{Section: "Base", Field: field, Message: fmt.Sprintf("undefined value %s", value)})
+		}
+	}
+
+	check("Sex", base.Sex.IsValid(), base.Sex)
+	check("LastFaction", base.LastFaction.IsValid(), base.LastFaction)
+	check("CurFaction", base.CurFaction.IsValid(), base.CurFaction)
+	check("FiveElement", base.FiveElement.IsValid(), base.FiveElement)
+	check("Camp", base.Camp.IsValid(), base.Camp)
+	check("PkStatus", base.PkStatus.IsValid(), base.PkStatus)
+
+	return issues
+}
+
+// maxSkillLevel : 获取技能最大等级，角色技能等级上限数据中的设置优先
+func maxSkillLevel(en *RoleEncoder, info *gamecatalog.SkillInfo) int {
+	for _, limit := range en.MaxSkillLevel {
+		for _, item := range limit.Data {
+			if int(item.SkillID) == info.ID && item.SkillID != 0 && int(item.SkillMaxLevel) > info.MaxLevel {
+				return int(item.SkillMaxLevel)
+			}
+		}
+	}
+	return info.MaxLevel
+}
+
+// validateRoleSkills : 检查技能是否属于当前门派、技能等级是否超过上限以及剩余技能点是否一致
+func (v *RoleValidator) validateRoleSkills(en *RoleEncoder) []RoleIssue {
+	var issues []RoleIssue
+
+	if v.Skills == nil {
+		return nil
+	}
+
+	base := &en.RoleBaseData
+	spent := 0
+
+	check := func(section string, skills []gmstruct.SkillData) {
+		for _, skill := range skills {
+			field := fmt.Sprintf("Skill[%d]", skill.SkillID)
+
+			info, ok := v.Skills.LookupSkill(int(skill.SkillID))
+			if !ok {
+				issues = append(issues, RoleIssue{Section: section, Field: field, Message: "unknown skill"})
+				continue
+			}
+			field += " " + info.Name
+
+			if info.Faction != gmstruct.FactionNone && info.Faction != base.CurFaction {
+				issues = append(issues, RoleIssue{Section: section, Field: field,
+					Message: fmt.Sprintf("skill of faction %s, but current faction is %s", info.Faction, base.CurFaction)})
+			}
+
+			if max := maxSkillLevel(en, info); max > 0 && int(skill.SkillLv) > max {
+				issues = append(issues, RoleIssue{Section: section, Field: field,
+					Message: fmt.Sprintf("level %d exceeds max level %d", skill.SkillLv, max)})
+			}
+
+			if info.UsePoint && skill.SkillLv > 0 {
+				spent += int(skill.SkillLv)
+			}
+		}
+	}
+
+	check("FSkill", en.FSkillData)
+	check("LSkill", en.LSkillData)
+
+	if base.LeftSkillPoint < 0 {
+		issues = append(issues, RoleIssue{Section: "Base", Field: "LeftSkillPoint",
+			Message: fmt.Sprintf("negative skill point %d", base.LeftSkillPoint)})
+	}
+
+	if total, ok := v.SkillPoints.PointsAt(int(base.RoleLevel)); ok {
+		if used := spent + int(base.LeftSkillPoint); used != total {
+			issues = append(issues, RoleIssue{Section: "Base", Field: "LeftSkillPoint",
+				Message: fmt.Sprintf("spent %d + left %d = %d, expected %d at level %d", spent, base.LeftSkillPoint, used, total, base.RoleLevel)})
+		}
+	}
+
+	return issues
+}
+
+// validateRoleLocation : 检查重生点和登出地图是否存在、登出坐标是否位于地图范围内以及是否停留在副本地图中
+func (v *RoleValidator) validateRoleLocation(en *RoleEncoder) []RoleIssue {
+	var issues []RoleIssue
+
+	if v.Maps == nil {
+		return nil
+	}
+
+	base := &en.RoleBaseData
+	add := func(field, format string, args ...interface{}) {
+		issues = append(issues, RoleIssue{Section: "Location", Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if revival, ok := v.Maps.LookupMap(int(base.RevivalID)); !ok {
+		add("RevivalID", "unknown map %d", base.RevivalID)
+	} else if revival.Instanced {
+		add("RevivalID", "revival map %d %s is an instanced map", base.RevivalID, revival.Name)
+	}
+
+	subWorld, ok := v.Maps.LookupMap(int(base.SubWorldID))
+	if !ok {
+		add("SubWorldID", "unknown map %d", base.SubWorldID)
+	} else {
+		if !subWorld.Contains(base.SubWorldMpsX, base.SubWorldMpsY) {
+			add("SubWorldMps", "position (%d, %d) out of map %d %s bounds (%d, %d)-(%d, %d)",
+				base.SubWorldMpsX, base.SubWorldMpsY, base.SubWorldID, subWorld.Name,
+				subWorld.MinX, subWorld.MinY, subWorld.MaxX, subWorld.MaxY)
+		}
+		if subWorld.Instanced {
+			add("SubWorldID", "logout map %d %s is an instanced map, copy index %d", base.SubWorldID, subWorld.Name, base.MapCopyIndex)
+		}
+	}
+
+	// 副本序号只在副本地图中有效，非副本地图中不为0说明登出时副本状态未清理
+	if base.MapCopyIndex != 0 && (!ok || !subWorld.Instanced) {
+		add("MapCopyIndex", "copy index %d on non-instanced map %d", base.MapCopyIndex, base.SubWorldID)
+	}
+
+	return issues
+}
@@ -0,0 +1,342 @@
+package gameinventory
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+const (
+	emptyCell       = -1 // 空闲格子
+	defaultGridSize = 1  // 物品默认尺寸
+)
+
+// Container : 物品容器定义，存储空间ID和尺寸没有内置默认值，需要由LoadContainers从经过核对的配置文件加载
+type Container struct {
+	Place      int32  // 物品存储空间
+	Name       string // 容器名称
+	Width      int    // 容器宽度（格子数）
+	Height     int    // 容器高度（格子数）
+	Slots      bool   // 是否为装备栏类容器，每个格子只能放一件物品且不考虑物品尺寸
+	ExtBoxFlag byte   // 需要开启的扩展箱标志位，0表示始终可用
+}
+
+// ItemSizer : 获取物品占用的格子尺寸
+type ItemSizer interface {
+	ItemSize(item *gmstruct.ItemData) (width, height int)
+}
+
+// Options : 物品布局选项
+type Options struct {
+	Containers []Container // 容器布局，由LoadContainers加载，不能为空
+	Sizer      ItemSizer   // 物品尺寸，为空时所有物品按1x1处理
+}
+
+// IssueType : 物品布局问题类型
+type IssueType int
+
+// 物品布局问题类型定义
+const (
+	IssueUnknownPlace   IssueType = iota // 未知的存储空间
+	IssueDisabledExtBox                  // 扩展箱未开启
+	IssueOutOfBounds                     // 坐标超出容器范围
+	IssueOverlap                         // 与其他物品重叠
+)
+
+// Issue : 物品布局问题
+type Issue struct {
+	Type       IssueType // 问题类型
+	ItemIndex  int       // 物品在ItemData中的索引
+	OtherIndex int       // 重叠物品的索引，仅IssueOverlap有效
+	Place      int32     // 物品存储空间
+	X          int       // 物品X坐标
+	Y          int       // 物品Y坐标
+}
+
+// String : 格式化布局问题
+func (i Issue) String() string {
+	switch i.Type {
+	case IssueUnknownPlace:
+		return fmt.Sprintf("Item[%d] : unknown place %d", i.ItemIndex, i.Place)
+	case IssueDisabledExtBox:
+		return fmt.Sprintf("Item[%d] : ext box of place %d is not opened", i.ItemIndex, i.Place)
+	case IssueOutOfBounds:
+		return fmt.Sprintf("Item[%d] : position (%d, %d) out of bounds in place %d", i.ItemIndex, i.X, i.Y, i.Place)
+	case IssueOverlap:
+		return fmt.Sprintf("Item[%d] : position (%d, %d) in place %d overlaps Item[%d]", i.ItemIndex, i.X, i.Y, i.Place, i.OtherIndex)
+	}
+	return fmt.Sprintf("Item[%d] : unknown issue %d", i.ItemIndex, i.Type)
+}
+
+// Grid : 容器格子数据
+type Grid struct {
+	Container
+	Enabled bool    // 容器是否可用（扩展箱是否开启）
+	Items   []int   // 放置在容器中的物品索引
+	cells   [][]int // 每个格子中的物品索引，emptyCell表示空
+}
+
+func newGrid(c Container, extBox byte) *Grid {
+	g := &Grid{Container: c}
+	g.Enabled = c.ExtBoxFlag == 0 || extBox&c.ExtBoxFlag != 0
+	g.cells = make([][]int, c.Height)
+	for y := range g.cells {
+		g.cells[y] = make([]int, c.Width)
+		for x := range g.cells[y] {
+			g.cells[y][x] = emptyCell
+		}
+	}
+	return g
+}
+
+// Cell : 获取格子中的物品索引，格子为空或超出范围时返回false
+func (g *Grid) Cell(x, y int) (int, bool) {
+	if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+		return emptyCell, false
+	}
+	index := g.cells[y][x]
+	return index, index != emptyCell
+}
+
+// FreeCells : 获取空闲格子数量
+func (g *Grid) FreeCells() int {
+	count := 0
+	for _, row := range g.cells {
+		for _, index := range row {
+			if index == emptyCell {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// FindFree : 按行优先查找可以放下指定尺寸物品的空闲位置
+func (g *Grid) FindFree(width, height int) (x, y int, ok bool) {
+	if !g.Enabled {
+		return 0, 0, false
+	}
+	if g.Slots {
+		width, height = defaultGridSize, defaultGridSize
+	}
+
+	for y = 0; y+height <= g.Height; y++ {
+		for x = 0; x+width <= g.Width; x++ {
+			if g.isAreaFree(x, y, width, height) {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func (g *Grid) isAreaFree(x, y, width, height int) bool {
+	for j := y; j < y+height; j++ {
+		for i := x; i < x+width; i++ {
+			if g.cells[j][i] != emptyCell {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Render : 以文本格式输出容器格子，格子中显示物品索引，'.'表示空闲
+func (g *Grid) Render(w io.Writer) {
+	state := ""
+	if !g.Enabled {
+		state = " (未开启)"
+	}
+	fmt.Fprintf(w, "[%d] %s %dx%d%s, Items = %d, Free = %d\n", g.Place, g.Name, g.Width, g.Height, state, len(g.Items), g.FreeCells())
+
+	for _, row := range g.cells {
+		for _, index := range row {
+			if index == emptyCell {
+				fmt.Fprintf(w, " %4s", ".")
+			} else {
+				fmt.Fprintf(w, " %4d", index)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// String : 以文本格式输出容器格子
+func (g *Grid) String() string {
+	var buf bytes.Buffer
+	g.Render(&buf)
+	return buf.String()
+}
+
+// Location : 物品所在位置
+type Location struct {
+	Place int32  // 物品存储空间
+	Name  string // 容器名称
+	X     int    // 物品X坐标
+	Y     int    // 物品Y坐标
+}
+
+// Inventory : 角色物品布局
+type Inventory struct {
+	Grids  []*Grid // 所有容器
+	Issues []Issue // 物品布局问题
+	sizer  ItemSizer
+	items  []gmstruct.ItemData
+}
+
+// Build : 根据角色基础数据和物品数据构建物品布局，没有容器布局时返回错误
+func Build(base *gmstruct.RoleBaseInfo, items []gmstruct.ItemData, opts *Options) (*Inventory, error) {
+	if opts == nil || len(opts.Containers) == 0 {
+		return nil, fmt.Errorf("gameinventory: no container layout, use LoadContainers")
+	}
+
+	inv := &Inventory{items: items, sizer: opts.Sizer}
+	for _, c := range opts.Containers {
+		inv.Grids = append(inv.Grids, newGrid(c, base.ExtBox))
+	}
+
+	for i := range items {
+		inv.place(i)
+	}
+
+	return inv, nil
+}
+
+// Grid : 根据存储空间获取容器
+func (inv *Inventory) Grid(place int32) *Grid {
+	for _, g := range inv.Grids {
+		if g.Place == place {
+			return g
+		}
+	}
+	return nil
+}
+
+// ItemSize : 获取物品占用的格子尺寸
+func (inv *Inventory) ItemSize(item *gmstruct.ItemData) (width, height int) {
+	if inv.sizer == nil {
+		return defaultGridSize, defaultGridSize
+	}
+
+	width, height = inv.sizer.ItemSize(item)
+	if width <= 0 {
+		width = defaultGridSize
+	}
+	if height <= 0 {
+		height = defaultGridSize
+	}
+	return width, height
+}
+
+// Locate : 获取物品所在位置
+func (inv *Inventory) Locate(index int) (Location, bool) {
+	if index < 0 || index >= len(inv.items) {
+		return Location{}, false
+	}
+
+	std := &inv.items[index].Standard
+	loc := Location{Place: std.Place, X: int(std.PosX), Y: int(std.PosY)}
+	if g := inv.Grid(std.Place); g != nil {
+		loc.Name = g.Name
+	}
+	return loc, true
+}
+
+// LocateGUID : 根据物品GUID（锁魂数据或账单数据中的GUID）获取物品索引和所在位置
+func (inv *Inventory) LocateGUID(guid int64) (int, Location, bool) {
+	for i := range inv.items {
+		item := &inv.items[i]
+		if (item.HasLockSoul && item.LockSoul.ItemGUID == guid) || (item.HasBill && item.Bill.ItemGUID == guid) {
+			loc, ok := inv.Locate(i)
+			return i, loc, ok
+		}
+	}
+	return emptyCell, Location{}, false
+}
+
+// FindFree : 按容器顺序查找可以放下指定物品的空闲位置
+func (inv *Inventory) FindFree(item *gmstruct.ItemData, places ...int32) (Location, bool) {
+	width, height := inv.ItemSize(item)
+
+	for _, place := range places {
+		g := inv.Grid(place)
+		if g == nil {
+			continue
+		}
+		if x, y, ok := g.FindFree(width, height); ok {
+			return Location{Place: place, Name: g.Name, X: x, Y: y}, true
+		}
+	}
+	return Location{}, false
+}
+
+// Render : 以文本格式输出所有容器格子
+func (inv *Inventory) Render(w io.Writer) {
+	for _, g := range inv.Grids {
+		g.Render(w)
+	}
+
+	if len(inv.Issues) > 0 {
+		fmt.Fprintf(w, "Issues = %d\n", len(inv.Issues))
+		for _, issue := range inv.Issues {
+			fmt.Fprintln(w, issue.String())
+		}
+	}
+}
+
+// String : 以文本格式输出所有容器格子
+func (inv *Inventory) String() string {
+	var buf bytes.Buffer
+	inv.Render(&buf)
+	return buf.String()
+}
+
+// place : 将物品放入所在容器，记录布局问题
+func (inv *Inventory) place(index int) {
+	std := &inv.items[index].Standard
+	x, y := int(std.PosX), int(std.PosY)
+	issue := Issue{ItemIndex: index, OtherIndex: emptyCell, Place: std.Place, X: x, Y: y}
+
+	g := inv.Grid(std.Place)
+	if g == nil {
+		issue.Type = IssueUnknownPlace
+		inv.Issues = append(inv.Issues, issue)
+		return
+	}
+
+	g.Items = append(g.Items, index)
+	if !g.Enabled {
+		issue.Type = IssueDisabledExtBox
+		inv.Issues = append(inv.Issues, issue)
+	}
+
+	width, height := defaultGridSize, defaultGridSize
+	if !g.Slots {
+		width, height = inv.ItemSize(&inv.items[index])
+	}
+
+	if x+width > g.Width || y+height > g.Height {
+		issue.Type = IssueOutOfBounds
+		inv.Issues = append(inv.Issues, issue)
+		return
+	}
+
+	for j := y; j < y+height; j++ {
+		for i := x; i < x+width; i++ {
+			if other := g.cells[j][i]; other != emptyCell {
+				issue.Type = IssueOverlap
+				issue.OtherIndex = other
+				inv.Issues = append(inv.Issues, issue)
+				return
+			}
+		}
+	}
+
+	for j := y; j < y+height; j++ {
+		for i := x; i < x+width; i++ {
+			g.cells[j][i] = index
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package gameinventory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+	gmstruct "github.com/heartchord/jxonline/gamestruct"
+)
+
+const containersForTest = `
+[3]
+Name=bag
+Width=4
+Height=2
+
+[5]
+Name=ext
+Width=2
+Height=2
+ExtBoxFlag=1 ; 需要开启扩展箱1
+
+[2]
+Name=equip
+Width=3
+Height=1
+Slots=1
+`
+
+// loadContainersForTest : 解析测试用的容器布局
+func loadContainersForTest(t *testing.T) []Container {
+	f, err := gamecatalog.ParseIniFile("containers.ini", strings.NewReader(containersForTest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers, err := ParseContainers(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return containers
+}
+
+// itemAt : 创建一个位于指定位置的物品
+func itemAt(place int32, x, y byte) gmstruct.ItemData {
+	item := gmstruct.ItemData{HasStandard: true}
+	item.Standard.Place = place
+	item.Standard.PosX = x
+	item.Standard.PosY = y
+	return item
+}
+
+// fixedSizer : 所有物品使用相同尺寸
+type fixedSizer struct{ width, height int }
+
+func (s fixedSizer) ItemSize(item *gmstruct.ItemData) (int, int) {
+	return s.width, s.height
+}
+
+func TestParseContainers(t *testing.T) {
+	containers := loadContainersForTest(t)
+	if len(containers) != 3 || containers[0].Place != 2 || containers[2].Place != 5 {
+		t.Fatalf("containers not sorted by place : %+v", containers)
+	}
+	if !containers[0].Slots || containers[2].ExtBoxFlag != 1 || containers[1].Name != "bag" {
+		t.Fatalf("bad container fields : %+v", containers)
+	}
+
+	f, _ := gamecatalog.ParseIniFile("bad.ini", strings.NewReader("[3]\nWidth=0\nHeight=2\n"))
+	if _, err := ParseContainers(f); err == nil {
+		t.Fatal("container without width accepted")
+	}
+}
+
+func TestBuildReportsIssues(t *testing.T) {
+	items := []gmstruct.ItemData{
+		itemAt(3, 0, 0), // 0 : 正常
+		itemAt(3, 0, 0), // 1 : 与0重叠
+		itemAt(3, 4, 0), // 2 : 超出范围
+		itemAt(9, 0, 0), // 3 : 未知存储空间
+		itemAt(5, 0, 0), // 4 : 扩展箱未开启
+	}
+
+	var base gmstruct.RoleBaseInfo
+	inv, err := Build(&base, items, &Options{Containers: loadContainersForTest(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]IssueType{1: IssueOverlap, 2: IssueOutOfBounds, 3: IssueUnknownPlace, 4: IssueDisabledExtBox}
+	if len(inv.Issues) != len(want) {
+		t.Fatalf("issues = %v", inv.Issues)
+	}
+	for _, issue := range inv.Issues {
+		if want[issue.ItemIndex] != issue.Type {
+			t.Fatalf("unexpected issue %s", issue)
+		}
+	}
+	if index, ok := inv.Grid(3).Cell(0, 0); !ok || index != 0 {
+		t.Fatalf("cell (0, 0) = %d, %v", index, ok)
+	}
+
+	if _, err := Build(&base, items, nil); err == nil {
+		t.Fatal("build without container layout accepted")
+	}
+}
+
+func TestFindFree(t *testing.T) {
+	items := []gmstruct.ItemData{itemAt(3, 0, 0), itemAt(3, 2, 0)}
+
+	var base gmstruct.RoleBaseInfo
+	inv, err := Build(&base, items, &Options{Containers: loadContainersForTest(t), Sizer: fixedSizer{2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 背包已满，未开启的扩展箱不能使用，装备栏不考虑物品尺寸
+	item := itemAt(0, 0, 0)
+	if _, ok := inv.FindFree(&item, 3, 5); ok {
+		t.Fatal("found free cell in full bag or closed ext box")
+	}
+	loc, ok := inv.FindFree(&item, 3, 5, 2)
+	if !ok || loc.Place != 2 || loc.X != 0 || loc.Y != 0 {
+		t.Fatalf("free location = %+v, %v", loc, ok)
+	}
+
+	base.ExtBox = 1
+	inv, _ = Build(&base, items, &Options{Containers: loadContainersForTest(t), Sizer: fixedSizer{2, 2}})
+	if loc, ok = inv.FindFree(&item, 3, 5); !ok || loc.Place != 5 {
+		t.Fatalf("opened ext box not used : %+v, %v", loc, ok)
+	}
+}
@@ -0,0 +1,68 @@
+package gameinventory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/heartchord/jxonline/gamecatalog"
+)
+
+// DefaultContainerFile : 物品容器布局INI文件的默认路径
+//
+// 配置文件由我们自行维护，取值需要与服务端的物品存储空间定义核对，每个容器一段，段名为存储空间ID：
+//
+//	[3]
+//	Name=背包
+//	Width=6
+//	Height=10
+//	Slots=0
+//	ExtBoxFlag=0
+//
+// Slots为1表示装备栏类容器，ExtBoxFlag为需要开启的扩展箱标志位
+const DefaultContainerFile = "containers.ini"
+
+// LoadContainers : 从配置目录加载物品容器布局
+func LoadContainers(s *gamecatalog.Settings, file string) ([]Container, error) {
+	f, err := s.IniFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseContainers(f)
+}
+
+// ParseContainers : 解析物品容器布局INI文件，按存储空间ID排序
+func ParseContainers(f *gamecatalog.IniFile) ([]Container, error) {
+	var containers []Container
+
+	for _, section := range f.Sections() {
+		place, err := strconv.ParseInt(section, 10, 32)
+		if err != nil || place <= 0 {
+			return nil, fmt.Errorf("gameinventory: container file %s : bad place [%s]", f.Name, section)
+		}
+
+		c := Container{
+			Place:      int32(place),
+			Width:      f.Int(section, "Width", 0),
+			Height:     f.Int(section, "Height", 0),
+			Slots:      f.Int(section, "Slots", 0) != 0,
+			ExtBoxFlag: byte(f.Int(section, "ExtBoxFlag", 0)),
+		}
+		c.Name, _ = f.String(section, "Name")
+		if c.Name == "" {
+			c.Name = section
+		}
+
+		if c.Width <= 0 || c.Height <= 0 || c.Width > 255 || c.Height > 255 {
+			return nil, fmt.Errorf("gameinventory: container file %s : bad size %dx%d in [%s]", f.Name, c.Width, c.Height, section)
+		}
+		containers = append(containers, c)
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("gameinventory: container file %s has no container", f.Name)
+	}
+
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Place < containers[j].Place })
+	return containers, nil
+}
@@ -0,0 +1,140 @@
+package gamestruct
+
+import (
+	"fmt"
+
+	"github.com/henrylee2cn/mahonia"
+)
+
+// GBKNameSize : 角色名、帐号名等定长GBK字符串的字节数（包含'\0'结束符）
+const GBKNameSize = 32
+
+// GBKName : 32字节定长GBK字符串，以'\0'结束，用于角色名、帐号名、物品归属人等字段
+type GBKName [GBKNameSize]byte
+
+// gbkValidLen : 获取GBK字节串中不会截断双字节字符的最大长度
+func gbkValidLen(b []byte) int {
+	i := 0
+	for i < len(b) {
+		if b[i] < 0x80 {
+			i++
+		} else {
+			i += 2
+		}
+	}
+	if i > len(b) { // 末尾是不完整的双字节字符
+		return len(b) - 1
+	}
+	return len(b)
+}
+
+// DecodeGBK : 将GBK字节串转换为UTF-8字符串，末尾不完整的双字节字符会被丢弃
+func DecodeGBK(b []byte) string {
+	b = b[:gbkValidLen(b)]
+	return mahonia.NewDecoder("GBK").ConvertString(string(b))
+}
+
+// EncodeGBK : 将UTF-8字符串转换为GBK字节串，含有GBK无法表示的字符时返回false
+func EncodeGBK(s string) ([]byte, bool) {
+	ret, ok := mahonia.NewEncoder("GBK").ConvertStringOK(s)
+	return []byte(ret), ok
+}
+
+// truncateGBK : 将UTF-8字符串转换为不超过maxLen字节的GBK字节串，按字符截断
+// 返回值ok为false表示字符串被截断或含有GBK无法表示的字符
+func truncateGBK(s string, maxLen int) (ret []byte, ok bool) {
+	encoder := mahonia.NewEncoder("GBK")
+
+	ok = true
+	for _, r := range s {
+		c, valid := encoder.ConvertStringOK(string(r))
+		if !valid {
+			ok = false
+			continue
+		}
+		if len(ret)+len(c) > maxLen {
+			return ret, false
+		}
+		ret = append(ret, c...)
+	}
+	return ret, ok
+}
+
+// GBKLen : 获取UTF-8字符串转换为GBK后的字节数，含有GBK无法表示的字符时返回false
+func GBKLen(s string) (int, bool) {
+	b, ok := EncodeGBK(s)
+	return len(b), ok
+}
+
+// NewGBKName : 根据UTF-8字符串创建GBKName，字符串被截断或含有无法表示的字符时返回false
+func NewGBKName(s string) (GBKName, bool) {
+	var n GBKName
+	ok := n.Set(s)
+	return n, ok
+}
+
+// Bytes : 获取'\0'之前的GBK字节串，'\0'之后残留的数据被忽略
+func (n GBKName) Bytes() []byte {
+	for i, c := range n {
+		if c == 0 {
+			return append([]byte(nil), n[:i]...)
+		}
+	}
+	return append([]byte(nil), n[:]...)
+}
+
+// Len : 获取'\0'之前的GBK字节数
+func (n GBKName) Len() int {
+	for i, c := range n {
+		if c == 0 {
+			return i
+		}
+	}
+	return len(n)
+}
+
+// IsEmpty : 字符串是否为空
+func (n GBKName) IsEmpty() bool {
+	return n[0] == 0
+}
+
+// String : 获取UTF-8字符串
+func (n GBKName) String() string {
+	return DecodeGBK(n.Bytes())
+}
+
+// Set : 设置UTF-8字符串，超长时按字符截断并保留'\0'结束符，剩余空间清零
+// 返回false表示字符串被截断或含有GBK无法表示的字符
+func (n *GBKName) Set(s string) bool {
+	b, ok := truncateGBK(s, GBKNameSize-1)
+
+	*n = GBKName{}
+	copy(n[:], b)
+	return ok
+}
+
+// SetBytes : 直接设置GBK字节串，超长时按字符截断，返回false表示发生截断
+func (n *GBKName) SetBytes(b []byte) bool {
+	ok := true
+	if len(b) > GBKNameSize-1 {
+		b = b[:gbkValidLen(b[:GBKNameSize-1])]
+		ok = false
+	}
+
+	*n = GBKName{}
+	copy(n[:], b)
+	return ok
+}
+
+// MarshalText : 以UTF-8字符串导出
+func (n GBKName) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText : 解析UTF-8字符串，超长或含有无法表示的字符时返回错误
+func (n *GBKName) UnmarshalText(text []byte) error {
+	if !n.Set(string(text)) {
+		return fmt.Errorf("gamestruct: %q can not be stored as %d bytes GBK string", text, GBKNameSize)
+	}
+	return nil
+}
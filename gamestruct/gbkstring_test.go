@@ -0,0 +1,62 @@
+package gamestruct
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGBKNameSetTruncates(t *testing.T) {
+	var n GBKName
+	if !n.Set(strings.Repeat("a", GBKNameSize-1)) || n.Len() != GBKNameSize-1 {
+		t.Fatalf("name of %d bytes not stored : len = %d", GBKNameSize-1, n.Len())
+	}
+
+	// 超长时截断并保留'\0'结束符
+	if n.Set(strings.Repeat("b", GBKNameSize)) {
+		t.Fatal("truncation not reported")
+	}
+	if n.Len() != GBKNameSize-1 || n[GBKNameSize-1] != 0 {
+		t.Fatalf("bad truncated name : len = %d, last = %d", n.Len(), n[GBKNameSize-1])
+	}
+
+	// 较短的名称覆盖时剩余空间清零
+	n.Set("c")
+	if n.Len() != 1 || !bytes.Equal(n[1:], make([]byte, GBKNameSize-1)) {
+		t.Fatal("residue left after shorter name")
+	}
+}
+
+func TestGBKNameSetBytesKeepsDoubleByteChars(t *testing.T) {
+	// 16个双字节字符共32字节，截断时不能留下半个字符
+	b := bytes.Repeat([]byte{0xB0, 0xA1}, 16)
+
+	var n GBKName
+	if n.SetBytes(b) {
+		t.Fatal("truncation not reported")
+	}
+	if n.Len() != 30 || !bytes.Equal(n.Bytes(), b[:30]) {
+		t.Fatalf("bad truncated name : len = %d", n.Len())
+	}
+}
+
+func TestGBKNameIgnoresResidue(t *testing.T) {
+	var n GBKName
+	copy(n[:], "abc\x00xyz")
+	if n.Len() != 3 || string(n.Bytes()) != "abc" || n.String() != "abc" {
+		t.Fatalf("residue after '\\0' not ignored : %q", n.Bytes())
+	}
+	if n.IsEmpty() || !(GBKName{}).IsEmpty() {
+		t.Fatal("bad IsEmpty")
+	}
+}
+
+func TestGBKNameUnmarshalText(t *testing.T) {
+	var n GBKName
+	if err := n.UnmarshalText([]byte("role")); err != nil || n.String() != "role" {
+		t.Fatalf("unmarshal failed : %v", err)
+	}
+	if err := n.UnmarshalText([]byte(strings.Repeat("x", GBKNameSize))); err == nil {
+		t.Fatal("overlong name accepted")
+	}
+}
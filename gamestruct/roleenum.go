@@ -0,0 +1,374 @@
+package gamestruct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// enumName : 枚举值的中英文名称
+type enumName struct {
+	value int64
+	cn    string
+	en    string
+}
+
+func lookupEnumName(names []enumName, value int64) (enumName, bool) {
+	for _, n := range names {
+		if n.value == value {
+			return n, true
+		}
+	}
+	return enumName{}, false
+}
+
+// parseEnumName : 解析枚举名称，支持中文名、英文名（不区分大小写）和数值
+func parseEnumName(names []enumName, s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	for _, n := range names {
+		if s == n.cn || strings.EqualFold(s, n.en) {
+			return n.value, true
+		}
+	}
+
+	// 未定义的枚举值以"类型名(数值)"格式输出
+	if i := strings.IndexByte(s, '('); i >= 0 && strings.HasSuffix(s, ")") {
+		s = s[i+1 : len(s)-1]
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func enumString(names []enumName, typeName string, value int64) string {
+	if n, ok := lookupEnumName(names, value); ok {
+		return n.cn
+	}
+	return fmt.Sprintf("%s(%d)", typeName, value)
+}
+
+func enumEnglishName(names []enumName, typeName string, value int64) string {
+	if n, ok := lookupEnumName(names, value); ok {
+		return n.en
+	}
+	return fmt.Sprintf("%s(%d)", typeName, value)
+}
+
+// Faction : 门派
+type Faction byte
+
+// 门派定义
+const (
+	FactionShaolin  Faction = 0   // 少林
+	FactionTianwang Faction = 1   // 天王
+	FactionTangmen  Faction = 2   // 唐门
+	FactionWudu     Faction = 3   // 五毒
+	FactionEmei     Faction = 4   // 峨嵋
+	FactionCuiyan   Faction = 5   // 翠烟
+	FactionGaibang  Faction = 6   // 丐帮
+	FactionTianren  Faction = 7   // 天忍
+	FactionWudang   Faction = 8   // 武当
+	FactionKunlun   Faction = 9   // 昆仑
+	FactionHuashan  Faction = 10  // 华山
+	FactionNone     Faction = 255 // 未加入门派(-1)
+)
+
+var factionNames = []enumName{
+	{int64(FactionShaolin), "少林", "Shaolin"},
+	{int64(FactionTianwang), "天王", "Tianwang"},
+	{int64(FactionTangmen), "唐门", "Tangmen"},
+	{int64(FactionWudu), "五毒", "Wudu"},
+	{int64(FactionEmei), "峨嵋", "Emei"},
+	{int64(FactionCuiyan), "翠烟", "Cuiyan"},
+	{int64(FactionGaibang), "丐帮", "Gaibang"},
+	{int64(FactionTianren), "天忍", "Tianren"},
+	{int64(FactionWudang), "武当", "Wudang"},
+	{int64(FactionKunlun), "昆仑", "Kunlun"},
+	{int64(FactionHuashan), "华山", "Huashan"},
+	{int64(FactionNone), "无门派", "None"},
+}
+
+// IsValid : 是否为已定义的门派
+func (f Faction) IsValid() bool {
+	_, ok := lookupEnumName(factionNames, int64(f))
+	return ok
+}
+
+// String : 门派中文名称
+func (f Faction) String() string {
+	return enumString(factionNames, "Faction", int64(f))
+}
+
+// EnglishName : 门派英文名称
+func (f Faction) EnglishName() string {
+	return enumEnglishName(factionNames, "Faction", int64(f))
+}
+
+// MarshalText : 以中文名称导出
+func (f Faction) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText : 解析中文名称、英文名称或数值
+func (f *Faction) UnmarshalText(text []byte) error {
+	v, ok := ParseFaction(string(text))
+	if !ok {
+		return fmt.Errorf("gamestruct: invalid faction %q", text)
+	}
+	*f = v
+	return nil
+}
+
+// ParseFaction : 解析门派名称，支持中文名、英文名和数值（-1表示未加入门派）
+func ParseFaction(s string) (Faction, bool) {
+	v, ok := parseEnumName(factionNames, s)
+	if !ok {
+		return 0, false
+	}
+	if v == -1 {
+		return FactionNone, true
+	}
+	if v < 0 || v > 255 {
+		return 0, false
+	}
+	return Faction(v), true
+}
+
+// Sex : 性别
+type Sex byte
+
+// 性别定义
+const (
+	SexMale   Sex = 0 // 男性
+	SexFemale Sex = 1 // 女性
+)
+
+var sexNames = []enumName{
+	{int64(SexMale), "男性", "Male"},
+	{int64(SexFemale), "女性", "Female"},
+}
+
+// IsValid : 是否为已定义的性别
+func (s Sex) IsValid() bool {
+	_, ok := lookupEnumName(sexNames, int64(s))
+	return ok
+}
+
+// String : 性别中文名称
+func (s Sex) String() string {
+	return enumString(sexNames, "Sex", int64(s))
+}
+
+// EnglishName : 性别英文名称
+func (s Sex) EnglishName() string {
+	return enumEnglishName(sexNames, "Sex", int64(s))
+}
+
+// MarshalText : 以中文名称导出
+func (s Sex) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText : 解析中文名称、英文名称或数值
+func (s *Sex) UnmarshalText(text []byte) error {
+	v, ok := ParseSex(string(text))
+	if !ok {
+		return fmt.Errorf("gamestruct: invalid sex %q", text)
+	}
+	*s = v
+	return nil
+}
+
+// ParseSex : 解析性别名称，支持中文名、英文名和数值
+func ParseSex(s string) (Sex, bool) {
+	v, ok := parseEnumName(sexNames, s)
+	if !ok || v < 0 || v > 255 {
+		return 0, false
+	}
+	return Sex(v), true
+}
+
+// Series : 五行
+type Series int32
+
+// 五行定义
+const (
+	SeriesMetal Series = 0 // 金
+	SeriesWood  Series = 1 // 木
+	SeriesWater Series = 2 // 水
+	SeriesFire  Series = 3 // 火
+	SeriesEarth Series = 4 // 土
+)
+
+var seriesNames = []enumName{
+	{int64(SeriesMetal), "金", "Metal"},
+	{int64(SeriesWood), "木", "Wood"},
+	{int64(SeriesWater), "水", "Water"},
+	{int64(SeriesFire), "火", "Fire"},
+	{int64(SeriesEarth), "土", "Earth"},
+}
+
+// IsValid : 是否为已定义的五行
+func (s Series) IsValid() bool {
+	_, ok := lookupEnumName(seriesNames, int64(s))
+	return ok
+}
+
+// String : 五行中文名称
+func (s Series) String() string {
+	return enumString(seriesNames, "Series", int64(s))
+}
+
+// EnglishName : 五行英文名称
+func (s Series) EnglishName() string {
+	return enumEnglishName(seriesNames, "Series", int64(s))
+}
+
+// MarshalText : 以中文名称导出
+func (s Series) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText : 解析中文名称、英文名称或数值
+func (s *Series) UnmarshalText(text []byte) error {
+	v, ok := ParseSeries(string(text))
+	if !ok {
+		return fmt.Errorf("gamestruct: invalid series %q", text)
+	}
+	*s = v
+	return nil
+}
+
+// ParseSeries : 解析五行名称，支持中文名、英文名和数值
+func ParseSeries(s string) (Series, bool) {
+	v, ok := parseEnumName(seriesNames, s)
+	if !ok || v < -1<<31 || v > 1<<31-1 {
+		return 0, false
+	}
+	return Series(v), true
+}
+
+// Camp : 阵营
+type Camp int32
+
+// 阵营定义，与服务端GameDataDef.h中的CAMP枚举一致，玩家角色只使用新手、正派、邪派、中立和杀手阵营
+const (
+	CampBegin    Camp = 0 // 新手
+	CampJustice  Camp = 1 // 正派
+	CampEvil     Camp = 2 // 邪派
+	CampBalance  Camp = 3 // 中立
+	CampFree     Camp = 4 // 杀手
+	CampAnimal   Camp = 5 // 野兽
+	CampEvent    Camp = 6 // 路人
+	CampAudience Camp = 7 // 观众
+)
+
+var campNames = []enumName{
+	{int64(CampBegin), "新手", "Begin"},
+	{int64(CampJustice), "正派", "Justice"},
+	{int64(CampEvil), "邪派", "Evil"},
+	{int64(CampBalance), "中立", "Balance"},
+	{int64(CampFree), "杀手", "Free"},
+	{int64(CampAnimal), "野兽", "Animal"},
+	{int64(CampEvent), "路人", "Event"},
+	{int64(CampAudience), "观众", "Audience"},
+}
+
+// IsValid : 是否为已定义的阵营
+func (c Camp) IsValid() bool {
+	_, ok := lookupEnumName(campNames, int64(c))
+	return ok
+}
+
+// String : 阵营中文名称
+func (c Camp) String() string {
+	return enumString(campNames, "Camp", int64(c))
+}
+
+// EnglishName : 阵营英文名称
+func (c Camp) EnglishName() string {
+	return enumEnglishName(campNames, "Camp", int64(c))
+}
+
+// MarshalText : 以中文名称导出
+func (c Camp) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText : 解析中文名称、英文名称或数值
+func (c *Camp) UnmarshalText(text []byte) error {
+	v, ok := ParseCamp(string(text))
+	if !ok {
+		return fmt.Errorf("gamestruct: invalid camp %q", text)
+	}
+	*c = v
+	return nil
+}
+
+// ParseCamp : 解析阵营名称，支持中文名、英文名和数值
+func ParseCamp(s string) (Camp, bool) {
+	v, ok := parseEnumName(campNames, s)
+	if !ok || v < -1<<31 || v > 1<<31-1 {
+		return 0, false
+	}
+	return Camp(v), true
+}
+
+// PkStatus : PK状态
+type PkStatus byte
+
+// PK状态定义
+const (
+	PkStatusPractice  PkStatus = 0 // 练功
+	PkStatusFight     PkStatus = 1 // 战斗
+	PkStatusSlaughter PkStatus = 2 // 屠杀
+)
+
+var pkStatusNames = []enumName{
+	{int64(PkStatusPractice), "练功", "Practice"},
+	{int64(PkStatusFight), "战斗", "Fight"},
+	{int64(PkStatusSlaughter), "屠杀", "Slaughter"},
+}
+
+// IsValid : 是否为已定义的PK状态
+func (p PkStatus) IsValid() bool {
+	_, ok := lookupEnumName(pkStatusNames, int64(p))
+	return ok
+}
+
+// String : PK状态中文名称
+func (p PkStatus) String() string {
+	return enumString(pkStatusNames, "PkStatus", int64(p))
+}
+
+// EnglishName : PK状态英文名称
+func (p PkStatus) EnglishName() string {
+	return enumEnglishName(pkStatusNames, "PkStatus", int64(p))
+}
+
+// MarshalText : 以中文名称导出
+func (p PkStatus) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText : 解析中文名称、英文名称或数值
+func (p *PkStatus) UnmarshalText(text []byte) error {
+	v, ok := ParsePkStatus(string(text))
+	if !ok {
+		return fmt.Errorf("gamestruct: invalid pk status %q", text)
+	}
+	*p = v
+	return nil
+}
+
+// ParsePkStatus : 解析PK状态名称，支持中文名、英文名和数值
+func ParsePkStatus(s string) (PkStatus, bool) {
+	v, ok := parseEnumName(pkStatusNames, s)
+	if !ok || v < 0 || v > 255 {
+		return 0, false
+	}
+	return PkStatus(v), true
+}
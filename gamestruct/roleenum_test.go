@@ -0,0 +1,67 @@
+package gamestruct
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumNamesRoundTrip(t *testing.T) {
+	for _, names := range [][]enumName{factionNames, sexNames, seriesNames, campNames, pkStatusNames} {
+		for _, n := range names {
+			for _, s := range []string{n.cn, n.en} {
+				v, ok := parseEnumName(names, s)
+				if !ok || v != n.value {
+					t.Fatalf("parse %q = %d, %v, want %d", s, v, ok, n.value)
+				}
+			}
+		}
+	}
+}
+
+func TestCampValues(t *testing.T) {
+	// 4为杀手阵营
+	if CampFree != 4 || CampFree.String() != "杀手" || !CampFree.IsValid() {
+		t.Fatalf("bad free camp : %d %s", CampFree, CampFree)
+	}
+	if c, ok := ParseCamp("free"); !ok || c != CampFree {
+		t.Fatalf("parse free = %d, %v", c, ok)
+	}
+	if Camp(8).IsValid() || Camp(8).String() != "Camp(8)" {
+		t.Fatalf("undefined camp : %s", Camp(8))
+	}
+	if c, ok := ParseCamp("Camp(8)"); !ok || c != 8 {
+		t.Fatalf("parse undefined camp = %d, %v", c, ok)
+	}
+}
+
+func TestFactionNone(t *testing.T) {
+	if f, ok := ParseFaction("-1"); !ok || f != FactionNone {
+		t.Fatalf("parse -1 = %d, %v", f, ok)
+	}
+	if _, ok := ParseFaction("300"); ok {
+		t.Fatal("faction out of byte range accepted")
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	type roleEnums struct {
+		Faction Faction
+		Sex     Sex
+		Series  Series
+		Camp    Camp
+		Pk      PkStatus
+	}
+	in := roleEnums{FactionWudang, Sex(1), Series(2), CampEvil, PkStatus(1)}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out roleEnums
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Fatalf("enums changed : %+v -> %+v", in, out)
+	}
+}
@@ -0,0 +1,83 @@
+package gamestruct
+
+// 角色经验在存档中被拆分为16位有符号高位和32位低位，组合后为48位有符号整数
+const (
+	MaxRoleExp = int64(1)<<47 - 1  // 可存储的最大经验值
+	MinRoleExp = -(int64(1) << 47) // 可存储的最小经验值（死亡惩罚可能导致经验为负）
+)
+
+// combineExp : 将经验高位与低位组合成64位经验值，低位按无符号数处理
+func combineExp(high int16, low int32) int64 {
+	return int64(high)<<32 | int64(uint32(low))
+}
+
+// splitExp : 将64位经验值拆分为高位与低位，超出存储范围时返回false
+func splitExp(exp int64) (high int16, low int32, ok bool) {
+	if exp < MinRoleExp || exp > MaxRoleExp {
+		return 0, 0, false
+	}
+	return int16(exp >> 32), int32(uint32(exp)), true
+}
+
+// Exp : 获取角色当前经验值
+func (r RoleBaseInfo) Exp() int64 {
+	return combineExp(r.ExpHigh, r.ExpLow)
+}
+
+// SetExp : 设置角色当前经验值，超出存储范围时不做修改并返回false
+func (r *RoleBaseInfo) SetExp(exp int64) bool {
+	high, low, ok := splitExp(exp)
+	if !ok {
+		return false
+	}
+	r.ExpHigh, r.ExpLow = high, low
+	return true
+}
+
+// LevelProgress : 根据经验表计算角色当前等级的升级进度
+func (r RoleBaseInfo) LevelProgress(table LevelExpTable) (float64, bool) {
+	return table.Progress(r.RoleLevel, r.Exp())
+}
+
+// Exp : 获取角色转灵经验值
+func (r RoleExtDataOfTransNimbus) Exp() int64 {
+	return combineExp(r.TransNimbusExpHigh, r.TransNimbusExpLow)
+}
+
+// SetExp : 设置角色转灵经验值，超出存储范围时不做修改并返回false
+func (r *RoleExtDataOfTransNimbus) SetExp(exp int64) bool {
+	high, low, ok := splitExp(exp)
+	if !ok {
+		return false
+	}
+	r.TransNimbusExpHigh, r.TransNimbusExpLow = high, low
+	return true
+}
+
+// LevelExpTable : 等级经验表，下标为角色等级，值为从该等级升到下一级所需经验
+type LevelExpTable []int64
+
+// NeedExp : 获取从指定等级升到下一级所需经验，经验表未覆盖该等级时返回false
+func (t LevelExpTable) NeedExp(level uint16) (int64, bool) {
+	if int(level) >= len(t) || t[level] <= 0 {
+		return 0, false
+	}
+	return t[level], true
+}
+
+// Progress : 计算指定等级和经验下的升级进度，0表示刚升级，1表示经验已满
+func (t LevelExpTable) Progress(level uint16, exp int64) (float64, bool) {
+	need, ok := t.NeedExp(level)
+	if !ok {
+		return 0, false
+	}
+
+	progress := float64(exp) / float64(need)
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 1 {
+		progress = 1
+	}
+	return progress, true
+}
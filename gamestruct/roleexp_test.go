@@ -0,0 +1,51 @@
+package gamestruct
+
+import (
+	"testing"
+)
+
+func TestRoleExpSplit(t *testing.T) {
+	values := []int64{0, 1, 0xFFFFFFFF, 0x100000000, 0x123456789AB, MaxRoleExp, -1, MinRoleExp}
+	for _, exp := range values {
+		var r RoleBaseInfo
+		if !r.SetExp(exp) {
+			t.Fatalf("exp %d not stored", exp)
+		}
+		if r.Exp() != exp {
+			t.Fatalf("exp %d read back as %d (high = %d, low = %d)", exp, r.Exp(), r.ExpHigh, r.ExpLow)
+		}
+	}
+
+	// 低位按无符号数处理，最高位为1时不能影响高位
+	r := RoleBaseInfo{ExpHigh: 1, ExpLow: -1}
+	if r.Exp() != 0x1FFFFFFFF {
+		t.Fatalf("exp = %#x, want 0x1ffffffff", r.Exp())
+	}
+
+	// 超出48位时不做修改
+	for _, exp := range []int64{MaxRoleExp + 1, MinRoleExp - 1} {
+		if r.SetExp(exp) || r.Exp() != 0x1FFFFFFFF {
+			t.Fatalf("exp %d out of range accepted", exp)
+		}
+	}
+}
+
+func TestLevelExpTableProgress(t *testing.T) {
+	table := LevelExpTable{0, 100, 200}
+
+	if p, ok := table.Progress(1, 50); !ok || p != 0.5 {
+		t.Fatalf("progress = %v, %v", p, ok)
+	}
+	if p, _ := table.Progress(2, 500); p != 1 {
+		t.Fatalf("progress = %v, want 1", p)
+	}
+	if p, _ := table.Progress(2, -10); p != 0 {
+		t.Fatalf("progress = %v, want 0", p)
+	}
+	if _, ok := table.Progress(0, 0); ok {
+		t.Fatal("level without exp accepted")
+	}
+	if _, ok := table.Progress(3, 0); ok {
+		t.Fatal("level out of table accepted")
+	}
+}
@@ -0,0 +1,55 @@
+package gamestruct
+
+import (
+	"fmt"
+)
+
+// RoleKeySize : 角色唯一标识的字节数
+const RoleKeySize = 32
+
+// RoleKey : 32字节定长角色唯一标识，内容为MD5的32位十六进制字符串，占满全部字节，没有'\0'结束符
+type RoleKey [RoleKeySize]byte
+
+// Bytes : 获取'\0'之前的字节串，标识占满全部字节时返回全部字节
+func (k RoleKey) Bytes() []byte {
+	for i, c := range k {
+		if c == 0 {
+			return append([]byte(nil), k[:i]...)
+		}
+	}
+	return append([]byte(nil), k[:]...)
+}
+
+// IsEmpty : 标识是否为空
+func (k RoleKey) IsEmpty() bool {
+	return k[0] == 0
+}
+
+// String : 获取标识字符串
+func (k RoleKey) String() string {
+	return string(k.Bytes())
+}
+
+// Set : 设置标识字符串，不足32字节时剩余空间清零，超长时返回false且不修改
+func (k *RoleKey) Set(s string) bool {
+	if len(s) > RoleKeySize {
+		return false
+	}
+
+	*k = RoleKey{}
+	copy(k[:], s)
+	return true
+}
+
+// MarshalText : 以字符串导出
+func (k RoleKey) MarshalText() ([]byte, error) {
+	return k.Bytes(), nil
+}
+
+// UnmarshalText : 解析标识字符串，超过32字节时返回错误
+func (k *RoleKey) UnmarshalText(text []byte) error {
+	if !k.Set(string(text)) {
+		return fmt.Errorf("gamestruct: role key %q longer than %d bytes", text, RoleKeySize)
+	}
+	return nil
+}
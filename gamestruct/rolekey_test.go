@@ -0,0 +1,37 @@
+package gamestruct
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRoleKeyJSONRoundTrip(t *testing.T) {
+	// MD5的十六进制字符串占满32字节，没有'\0'结束符
+	hex := "0123456789abcdef0123456789abcdef"
+
+	var v struct{ Key RoleKey }
+	if !v.Key.Set(hex) {
+		t.Fatal("32 bytes key not stored")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), hex) {
+		t.Fatalf("key not exported : %s", data)
+	}
+
+	var out struct{ Key RoleKey }
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Key != v.Key {
+		t.Fatalf("key changed : %s -> %s", v.Key, out.Key)
+	}
+
+	if err := out.Key.UnmarshalText([]byte(hex + "0")); err == nil {
+		t.Fatal("overlong key accepted")
+	}
+}
@@ -5,16 +5,16 @@ import "fmt"
 // RoleBaseInfo : 角色基础数据中的基础信息部分，包含角色最基本的属性状态
 type RoleBaseInfo struct {
 	RoleID              uint32   "当前未使用"
-	RoleName            [32]byte "角色名"
-	Sex                 byte     "性别：0-男性，1-女性"
-	Alias               [32]byte "当前未使用"
-	Account             [32]byte "帐号名"
-	LastFaction         byte     "上次加入门派：-1(255)-未加入门派，0-少林，1-天王，2-唐门，3-五毒，4-峨嵋，5-翠烟，6-丐帮，7-天忍，8-武当，9-昆仑，10-华山"
-	CurFaction          byte     "当前加入门派：-1(255)-未加入门派，0-少林，1-天王，2-唐门，3-五毒，4-峨嵋，5-翠烟，6-丐帮，7-天忍，8-武当，9-昆仑，10-华山"
+	RoleName            GBKName  "角色名"
+	Sex                 Sex      "性别：0-男性，1-女性"
+	Alias               GBKName  "当前未使用"
+	Account             GBKName  "帐号名"
+	LastFaction         Faction  "上次加入门派：-1(255)-未加入门派，0-少林，1-天王，2-唐门，3-五毒，4-峨嵋，5-翠烟，6-丐帮，7-天忍，8-武当，9-昆仑，10-华山"
+	CurFaction          Faction  "当前加入门派：-1(255)-未加入门派，0-少林，1-天王，2-唐门，3-五毒，4-峨嵋，5-翠烟，6-丐帮，7-天忍，8-武当，9-昆仑，10-华山"
 	FightMode           byte     "战斗状态：0-非战斗状态，1-战斗状态"
 	UseRevive           byte     "是否使用复活点复活"
 	IsExchanged         byte     "是否处于跨服状态"
-	PkStatus            byte     "PK状态"
+	PkStatus            PkStatus "PK状态"
 	AddFactionTimes     int32    "加入门总次数"
 	SectRole            int32    "未知含义"
 	GroupCode           int32    "当前未使用"
@@ -25,11 +25,11 @@ type RoleBaseInfo struct {
 	SubWorldID          int32    "上次登出地图ID"
 	SubWorldMpsX        int32    "上次登出地图X坐标"
 	SubWorldMpsY        int32    "上次登出地图Y坐标"
-	PrimaryKey          [32]byte "角色唯一标识，MD5"
+	PrimaryKey          RoleKey  "角色唯一标识，MD5的十六进制字符串"
 	BoxMoney            int32    "储物箱金钱"
 	BagMoney            int32    "身上金钱"
-	FiveElement         int32    "五行"
-	Camp                int32    "阵营"
+	FiveElement         Series   "五行"
+	Camp                Camp     "阵营"
 	RoleLevel           uint16   "角色等级"
 	ExpHigh             int16    "经验高位"
 	ExpLow              int32    "经验低位"
@@ -147,7 +147,7 @@ type ItemDataStd struct {
 	Level                     byte   // 物品等级
 	BindFlag                  byte   // 绑定标志 : 1-绑定中，0-解除绑定时间高位
 	DeBindTime                uint16 // 解除绑定时间低位（离2000年1月1日的小时数）
-	Series                    int32  // 五行
+	Series                    Series // 五行
 	Version                   int32  // 版本
 	RandSeed                  int32  // 随机数种子
 	Param2                    int32  // 物品扩展参数2
@@ -163,11 +163,11 @@ type ItemDataStd struct {
 
 // ItemDataLockSoul : a data struct of item lock soul data
 type ItemDataLockSoul struct {
-	Owner             [32]byte // 物品归属人
-	State             byte     // 锁魂状态
-	UnLockExpiredTime uint32   // 解魂到期时间
-	ItemGUID          int64    // 物品GUID
-	OwnerGUID         int64    // 归属人GUID
+	Owner             GBKName // 物品归属人
+	State             byte    // 锁魂状态
+	UnLockExpiredTime uint32  // 解魂到期时间
+	ItemGUID          int64   // 物品GUID
+	OwnerGUID         int64   // 归属人GUID
 }
 
 // ItemDataBill : a data struct of item bill data, if player buys item, the item will have this data
@@ -188,7 +188,7 @@ type ItemDataExtend struct {
 	StarStoneLevel  [5]uint16 // 装备上对应镶孔的等级
 	CurWishValue    uint16    // 装备当前幸运值
 	LastBreakTime   uint32    // 装备上次突破时间
-	OwnerName       [32]byte  // 装备所有者名字-十万VIP奖励
+	OwnerName       GBKName   // 装备所有者名字-十万VIP奖励
 	Reserved        [4]byte   // 预留空间
 }
 
@@ -272,6 +272,9 @@ const (
 	RoleTitleTypeCount             // 角色称号类型总数
 )
 
+// GameFPS : 游戏逻辑每秒帧数，用于换算游戏帧数称号的剩余时间
+const GameFPS = 18
+
 // RoleTitleTime : 角色称号时间
 type RoleTitleTime struct {
 	Type     int32 // 称号时间类型，跟称号类型定义一致
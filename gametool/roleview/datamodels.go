@@ -7,7 +7,7 @@ import (
 	"strconv"
 
 	"github.com/heartchord/goblazer"
-	"github.com/henrylee2cn/mahonia"
+	"github.com/heartchord/jxonline/gamestruct"
 	"github.com/lxn/walk"
 )
 
@@ -186,6 +186,12 @@ func (m *DataModel1) ResetRows(data interface{}) {
 	m.Sort(m.sortColumn, m.sortOrder)
 }
 
+// enumValue : gamestruct中定义的枚举类型（门派、性别、五行等）
+type enumValue interface {
+	fmt.Stringer
+	IsValid() bool
+}
+
 func getStructFieldStrings(s interface{}) []string {
 	var ret []string
 
@@ -194,8 +200,6 @@ func getStructFieldStrings(s interface{}) []string {
 		panic("Unexpected Data Type")
 	}
 
-	mdecoder := mahonia.NewDecoder("GBK")
-
 	count := o.NumField()
 	for i := 0; i < count; i++ {
 		v := o.Field(i)
@@ -205,6 +209,24 @@ func getStructFieldStrings(s interface{}) []string {
 			continue
 		}
 
+		// 定长GBK字符串，'\0'之后的残留数据不显示
+		if name, ok := v.Interface().(gamestruct.GBKName); ok {
+			ret = append(ret, name.String())
+			continue
+		}
+
+		// 枚举类型同时显示名称和原始数值，未定义的枚举值由String()输出数值
+		if e, ok := v.Interface().(enumValue); ok && e.IsValid() {
+			switch v.Kind() {
+			case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				ret = append(ret, fmt.Sprintf("%s(%d)", e.String(), v.Int()))
+				continue
+			case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				ret = append(ret, fmt.Sprintf("%s(%d)", e.String(), v.Uint()))
+				continue
+			}
+		}
+
 		if v.Kind() == reflect.Array || v.Kind() == reflect.Slice {
 			var slice []byte
 
@@ -225,7 +247,7 @@ func getStructFieldStrings(s interface{}) []string {
 				str = string(slice)
 			}
 
-			str = mdecoder.ConvertString(str)
+			str = gamestruct.DecodeGBK([]byte(str))
 			ret = append(ret, str)
 
 		} else {
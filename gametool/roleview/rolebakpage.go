@@ -12,7 +12,6 @@ import (
 
 	"github.com/heartchord/goblazer"
 	"github.com/heartchord/jxonline/gameencoder"
-	"github.com/henrylee2cn/mahonia"
 	"github.com/lxn/walk"
 	dcl "github.com/lxn/walk/declarative"
 )
@@ -212,10 +211,7 @@ func (pg *RoleBakPage) BakDecodeRoutineFunction(filePath string) {
 	data, err := ioutil.ReadAll(fi)
 	pg.encoder.Decode(data)
 
-	mdecoder := mahonia.NewDecoder("GBK")
-
-	roleName := string(pg.encoder.BakData.RoleNameGBK)
-	roleName = mdecoder.ConvertString(roleName)
+	roleName := pg.encoder.BakData.RoleName()
 	roleNameLen := fmt.Sprintf("%d", pg.encoder.BakData.RoleNameLen)
 	roleDataLen := fmt.Sprintf("%d", pg.encoder.BakData.RoleDataLen)
 
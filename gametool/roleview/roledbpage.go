@@ -7,7 +7,7 @@ import (
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/heartchord/jxonline/gameencoder"
-	"github.com/henrylee2cn/mahonia"
+	"github.com/heartchord/jxonline/gamestruct"
 	"github.com/lxn/walk"
 	dcl "github.com/lxn/walk/declarative"
 )
@@ -104,8 +104,6 @@ func (pg *RoleDbPage) queryRoleDBDataRoutineFunction() {
 
 	var data RoleDBData
 
-	mencoder := mahonia.NewEncoder("GBK")
-	mdecoder := mahonia.NewDecoder("GBK")
 	//fmt.Printf("IP = %s\n", pg.dbInfoBindData.IP)
 	//fmt.Printf("Port = %s\n", pg.dbInfoBindData.Port)
 	//fmt.Printf("Username = %s\n", pg.dbInfoBindData.Username)
@@ -144,7 +142,7 @@ func (pg *RoleDbPage) queryRoleDBDataRoutineFunction() {
 	// 查询数据
 	pg.WriteLog("开始查询角色数据")
 
-	name, ok := mencoder.ConvertStringOK(pg.dbInfoBindData.Rolename)
+	name, ok := gamestruct.EncodeGBK(pg.dbInfoBindData.Rolename)
 	if !ok {
 		pg.WriteLog(">> Error : 角色名转换编码[UTF8 -> GBK]失败")
 		return
@@ -194,8 +192,8 @@ func (pg *RoleDbPage) queryRoleDBDataRoutineFunction() {
 		}
 
 		data.ID = fmt.Sprintf("%d", id)
-		data.RoleName = mdecoder.ConvertString(username)
-		data.Account = mdecoder.ConvertString(account)
+		data.RoleName = gamestruct.DecodeGBK([]byte(username))
+		data.Account = gamestruct.DecodeGBK([]byte(account))
 		data.RoleData = roledata
 		data.LastModified = datetime.Format("2006-01-02 15:04:05")
 	}